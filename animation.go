@@ -0,0 +1,87 @@
+package countdown
+
+import (
+	"bytes"
+	"image"
+	"image/draw"
+	"image/gif"
+)
+
+// Animation is a sequence of background frames,
+// delays are in 100ths of a second like in GIF
+type Animation struct {
+	Frames []image.Image
+	Delays []int
+}
+
+// frameAt returns the index of the frame shown at time t from the start
+// and the time left until the next frame, the animation loops
+func (a *Animation) frameAt(t int) (index, left int) {
+	total := 0
+	for _, d := range a.Delays {
+		total += d
+	}
+
+	t %= total
+	for i, d := range a.Delays {
+		if t < d {
+			return i, d - t
+		}
+		t -= d
+	}
+	return 0, a.Delays[0]
+}
+
+// loadAnimation decodes frames of an animated GIF,
+// it returns nil if data is not a GIF or has a single frame
+func loadAnimation(data []byte) (*Animation, error) {
+	if !bytes.HasPrefix(data, []byte("GIF8")) {
+		return nil, nil
+	}
+
+	src, err := gif.DecodeAll(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if len(src.Image) < 2 {
+		return nil, nil
+	}
+
+	// GIF frames are patches drawn over the previous ones,
+	// so they are composed into full frames
+	canvas := image.NewRGBA(image.Rect(0, 0, src.Config.Width, src.Config.Height))
+	a := &Animation{}
+	for i, frame := range src.Image {
+		var previous *image.RGBA
+		disposal := byte(0)
+		if i < len(src.Disposal) {
+			disposal = src.Disposal[i]
+		}
+		if disposal == gif.DisposalPrevious {
+			previous = image.NewRGBA(canvas.Bounds())
+			copy(previous.Pix, canvas.Pix)
+		}
+
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+
+		full := image.NewRGBA(canvas.Bounds())
+		copy(full.Pix, canvas.Pix)
+		a.Frames = append(a.Frames, full)
+
+		// browsers play frames without delay at 10 fps
+		delay := src.Delay[i]
+		if delay < 2 {
+			delay = 10
+		}
+		a.Delays = append(a.Delays, delay)
+
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, frame.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			canvas = previous
+		}
+	}
+
+	return a, nil
+}
@@ -0,0 +1,107 @@
+package countdown
+
+import (
+	"fmt"
+	"image"
+	"time"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// fitFontSize sets the largest font size the timer fits the frame with at the margin.
+// Digits are measured as the widest one, so only the number of parts and digits matters,
+// which is the largest at the ends of the timer range and, if the fraction
+// of a second is shown, just below a minute.
+func (g *Generator) fitFontSize() error {
+	if g.Font == nil {
+		return fmt.Errorf("auto fit requires an OpenType font")
+	}
+
+	var format []token
+	if g.Format != "" {
+		var err error
+		format, err = parseFormat(g.Format)
+		if err != nil {
+			return fmt.Errorf("failed to parse format: %v", err)
+		}
+	}
+
+	first, last := g.TimeFrom, g.lastValue()
+	values := []time.Duration{first, last}
+	if lo, hi := min(first, last), max(first, last); lo < time.Minute && hi >= time.Minute {
+		values = append(values, time.Minute-time.Nanosecond)
+	}
+
+	var texts [][]segment
+	for _, v := range values {
+		texts = append(texts, g.parts(format, v))
+	}
+
+	// title and subtitle keep their size, so the timer gets the rest of the box
+	box := image.Rect(0, 0, g.Width, g.Height).Inset(g.FontAutoFitMargin)
+	box.Max.Y -= g.textBlocksHeight(image.Rect(0, 0, g.Width, g.Height))
+	fits := func(face font.Face) bool {
+		d := &font.Drawer{Face: face}
+		for _, parts := range texts {
+			w, h := g.timerSize(d, parts)
+			if w > fixed.I(box.Dx()) || h > box.Dy() {
+				return false
+			}
+		}
+
+		if g.ExpiredText != "" {
+			// expired text is a single line without labels
+			w, h := d.MeasureString(g.ExpiredText), face.Metrics().CapHeight.Ceil()
+			if w > fixed.I(box.Dx()) || h > box.Dy() {
+				return false
+			}
+		}
+		return true
+	}
+
+	// text width grows with the font size, but not exactly linearly
+	// because of hinting, so integer sizes are searched
+	var best font.Face
+	lo, hi := 1, max(box.Dx(), box.Dy())
+	for lo <= hi {
+		size := (lo + hi) / 2
+		face, err := newFace(g.Font, float64(size))
+		if err != nil {
+			return fmt.Errorf("failed to create font face: %v", err)
+		}
+		face, err = g.withFallback(face, float64(size))
+		if err != nil {
+			return err
+		}
+		if fits(face) {
+			best, g.FontSize = face, float64(size)
+			lo = size + 1
+		} else {
+			hi = size - 1
+		}
+	}
+
+	if best == nil {
+		return fmt.Errorf("timer doesn't fit in %dx%d", box.Dx(), box.Dy())
+	}
+	g.FontFace = best
+	return nil
+}
+
+// lastValue returns the value of the timer in the last frame
+func (g *Generator) lastValue() time.Duration {
+	maxFrames := g.MaxFrames
+	if g.CountUp && maxFrames == 0 {
+		maxFrames = defaultCountUpSeconds * g.FPS
+	}
+	if maxFrames == 0 {
+		return 0
+	}
+
+	step := time.Duration(maxFrames-1) * time.Second / time.Duration(g.FPS)
+	if g.CountUp {
+		return g.TimeFrom + step
+	}
+	return max(g.TimeFrom-step, 0)
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/chuhlomin/countdown"
 )
@@ -22,12 +24,23 @@ func main() {
 func run() error {
 	fontPath := flag.String("f", "", "path to font file")
 	fontSize := flag.Float64("s", 48, "font size")
+	fallbackFonts := flag.String("ff", "", "comma-separated paths to fallback fonts for missing glyphs (optional)")
+	digitSprites := flag.String("ds", "", "path to digit sprite sheet or directory with 0.png-9.png and colon.png (optional)")
+	fontAutoFit := flag.Bool("sa", false, "fit font size to the image")
+	fontAutoFitMargin := flag.Int("sam", 0, "margin around the auto fitted timer")
 	backgroundColor := flag.String("bg", "black", "background color")
 	backgroundImage := flag.String("bi", "", "path to background image (optional)")
 	textColor := flag.String("c", "white", "text color")
 	timeFrom := flag.Duration("from", 0, "duration to start countdown from")
 	targetTime := flag.Int("t", 0, "target time in Unix format")
+	countUp := flag.Bool("up", false, "count up instead of down")
+	startTime := flag.Int("st", 0, "start time in Unix format to count up from")
+	expiredText := flag.String("et", "", "text to show when countdown reaches zero")
+	expiredBackgroundImage := flag.String("ebi", "", "path to background image to show when countdown reaches zero")
+	expiredHold := flag.Bool("eh", false, "show zeros if target time has passed")
 	maxFrames := flag.Int("max", 0, "max frames")
+	fps := flag.Int("fps", 1, "frames per second")
+	fractionDigits := flag.Int("fd", 0, "fraction of second digits shown in the last minute (0-2)")
 	width := flag.Int("w", 600, "image width")
 	height := flag.Int("h", 400, "image height")
 	out := flag.String("o", "output.gif", "output file")
@@ -36,6 +49,64 @@ func run() error {
 	paletteMaxColors := flag.Int("pm", 0, "max colors in palette")
 	paletteMaxColorsAuto := flag.Bool("pma", false, "auto max colors in palette")
 	noLeadingZeros := flag.Bool("no0", false, "trim leading zeros")
+	units := flag.String("u", "", "units to show, e.g. dhms, dh, ms (default: auto)")
+	labels := flag.String("l", "", "comma-separated labels for days, hours, minutes and seconds")
+	labelFontPath := flag.String("lf", "", "path to labels font file")
+	labelFontSize := flag.Float64("ls", 16, "labels font size")
+	labelColor := flag.String("lc", "", "labels color (default: text color)")
+	labelSpacing := flag.Int("lsp", 8, "spacing between timer and labels")
+	locale := flag.String("locale", "", "locale for labels plural forms, e.g. en, de, ru")
+	numberingSystem := flag.String("ns", "", "numbering system for digits, e.g. arab, deva, thai")
+	labelAlign := flag.String("la", "center", "labels alignment: start, center or end")
+	rtl := flag.Bool("rtl", false, "right-to-left layout")
+	format := flag.String("fmt", "", "countdown format, e.g. \"{d}d {hh}h {mm}m\"")
+	flipClock := flag.Bool("flip", false, "flip clock style")
+	cardColor := flag.String("cardc", "#333", "flip clock card color")
+	cardRadius := flag.Int("cardr", 6, "flip clock card corner radius")
+	cardPadding := flag.Int("cardp", 8, "flip clock card padding")
+	transitionFrames := flag.Int("tf", 4, "number of transition frames")
+	transition := flag.String("tr", "", "digits transition: none, flip, slide, fade or roll")
+	transitionDuration := flag.Float64("td", 0.3, "fraction of a second taken by the transition")
+	blinkSeparator := flag.Bool("blink", false, "blink separators every second")
+	separatorDimColor := flag.String("sdc", "", "color of blinking separators instead of hiding them")
+	separators := flag.String("sep", "", "separators after days, hours and minutes, e.g. \"d ,h ,m\"")
+	separatorOffsets := flag.String("sepy", "", "vertical offsets of separators after days, hours and minutes")
+	separatorImage := flag.String("sepi", "", "path to separator image (optional)")
+	colorRules := flag.String("cr", "", "text color rules, e.g. \"s:orange,5m:red\"")
+	strokeWidth := flag.Int("sw", 0, "text stroke width")
+	strokeColor := flag.String("sc", "black", "text stroke color")
+	shadowColor := flag.String("shc", "", "text shadow color (optional)")
+	shadowOffsetX := flag.Int("shx", 2, "text shadow X offset")
+	shadowOffsetY := flag.Int("shy", 2, "text shadow Y offset")
+	shadowBlur := flag.Int("shb", 2, "text shadow blur radius")
+	backgroundGradient := flag.String("bgg", "", "background gradient, e.g. \"linear-gradient(to right, navy, purple)\"")
+	textGradient := flag.String("cg", "", "text gradient, e.g. \"linear-gradient(gold, orange)\"")
+	backgroundFit := flag.String("bif", "", "background image fit: none, cover, contain, stretch, tile or center")
+	backgroundAnchor := flag.String("bia", "", "background image anchor, e.g. center, top-left, bottom")
+	backgroundOffsetX := flag.Int("bix", 0, "background image X offset")
+	backgroundOffsetY := flag.Int("biy", 0, "background image Y offset")
+	timerAnchor := flag.String("ta", "", "timer anchor, e.g. center, top-left, bottom")
+	timerPadding := flag.Int("tpad", 0, "space between frame edges and the timer")
+	timerPosition := flag.String("tpos", "", "timer anchor point position as x,y (default: anchored)")
+	title := flag.String("title", "", "text above the timer")
+	titleFontPath := flag.String("titlef", "", "path to title font file")
+	titleFontSize := flag.Float64("titles", 16, "title font size")
+	titleColor := flag.String("titlec", "", "title color (default: text color)")
+	titleWidth := flag.Int("titlew", 0, "title wrapping width (default: image width)")
+	subtitle := flag.String("sub", "", "text below the timer")
+	subtitleFontPath := flag.String("subf", "", "path to subtitle font file")
+	subtitleFontSize := flag.Float64("subs", 16, "subtitle font size")
+	subtitleColor := flag.String("subc", "", "subtitle color (default: text color)")
+	subtitleWidth := flag.Int("subw", 0, "subtitle wrapping width (default: image width)")
+	progressBar := flag.Bool("pb", false, "show progress bar")
+	progressRing := flag.Bool("pr", false, "show progress ring")
+	progressAnchor := flag.String("pa", "", "progress anchor (default: bottom for bar, center for ring)")
+	progressColor := flag.String("pc", "", "progress color (default: text color)")
+	progressTrackColor := flag.String("ptc", "", "progress track color (optional)")
+	progressThickness := flag.Int("pt", 8, "progress thickness")
+	progressSize := flag.Int("ps", 0, "progress bar length or ring diameter (default: fill the image)")
+	progressOffsetX := flag.Int("px", 0, "progress X offset")
+	progressOffsetY := flag.Int("py", 0, "progress Y offset")
 	flag.Parse()
 
 	if *fontPath == "" {
@@ -47,14 +118,78 @@ func run() error {
 		countdown.WithHeight(*height),
 		countdown.WithFontSize(*fontSize),
 		countdown.WithFontPath(*fontPath),
+		countdown.WithDigitSpritesPath(*digitSprites),
 		countdown.WithBackgroundColor(*backgroundColor),
 		countdown.WithBackgroundImagePath(*backgroundImage),
+		countdown.WithBackgroundFit(*backgroundFit),
+		countdown.WithBackgroundAnchor(*backgroundAnchor),
+		countdown.WithBackgroundOffset(*backgroundOffsetX, *backgroundOffsetY),
 		countdown.WithTextColor(*textColor),
+		countdown.WithColorRules(*colorRules),
+		countdown.WithBackgroundGradient(*backgroundGradient),
+		countdown.WithTextGradient(*textGradient),
+		countdown.WithStrokeWidth(*strokeWidth),
+		countdown.WithStrokeColor(*strokeColor),
+		countdown.WithShadowColor(*shadowColor),
+		countdown.WithShadowOffset(*shadowOffsetX, *shadowOffsetY),
+		countdown.WithShadowBlur(*shadowBlur),
 		countdown.WithTimeFrom(*timeFrom),
 		countdown.WithTargetTime(*targetTime),
+		countdown.WithStartTime(*startTime),
+		countdown.WithExpiredText(*expiredText),
+		countdown.WithExpiredBackgroundImagePath(*expiredBackgroundImage),
 		countdown.WithMaxFrames(*maxFrames),
+		countdown.WithFPS(*fps),
+		countdown.WithFractionDigits(*fractionDigits),
 		countdown.WithColonCompensation(*colonCompensation),
 		countdown.WithPaletteMaxColors(*paletteMaxColors),
+		countdown.WithUnits(*units),
+		countdown.WithLabels(*labels),
+		countdown.WithLabelFontSize(*labelFontSize),
+		countdown.WithLabelFontPath(*labelFontPath),
+		countdown.WithLabelColor(*labelColor),
+		countdown.WithLabelSpacing(*labelSpacing),
+		countdown.WithLocale(*locale),
+		countdown.WithNumberingSystem(*numberingSystem),
+		countdown.WithLabelAlign(*labelAlign),
+		countdown.WithFormat(*format),
+		countdown.WithTitle(*title),
+		countdown.WithTitleFontSize(*titleFontSize),
+		countdown.WithTitleFontPath(*titleFontPath),
+		countdown.WithTitleColor(*titleColor),
+		countdown.WithTitleWidth(*titleWidth),
+		countdown.WithSubtitle(*subtitle),
+		countdown.WithSubtitleFontSize(*subtitleFontSize),
+		countdown.WithSubtitleFontPath(*subtitleFontPath),
+		countdown.WithSubtitleColor(*subtitleColor),
+		countdown.WithSubtitleWidth(*subtitleWidth),
+		countdown.WithProgressColor(*progressColor),
+		countdown.WithProgressTrackColor(*progressTrackColor),
+		countdown.WithProgressThickness(*progressThickness),
+		countdown.WithProgressSize(*progressSize),
+		countdown.WithProgressOffset(*progressOffsetX, *progressOffsetY),
+		countdown.WithTimerAnchor(*timerAnchor),
+		countdown.WithTimerPadding(*timerPadding),
+		countdown.WithCardColor(*cardColor),
+		countdown.WithCardRadius(*cardRadius),
+		countdown.WithCardPadding(*cardPadding),
+		countdown.WithTransitionFrames(*transitionFrames),
+		countdown.WithTransition(*transition),
+		countdown.WithTransitionDuration(*transitionDuration),
+		countdown.WithSeparatorDimColor(*separatorDimColor),
+		countdown.WithSeparators(*separators),
+		countdown.WithSeparatorOffsets(*separatorOffsets),
+		countdown.WithSeparatorImagePath(*separatorImage),
+	}
+
+	if *fallbackFonts != "" {
+		for _, path := range strings.Split(*fallbackFonts, ",") {
+			opts = append(opts, countdown.WithFallbackFontPath(path))
+		}
+	}
+
+	if *fontAutoFit {
+		opts = append(opts, countdown.WithFontAutoFit(*fontAutoFitMargin))
 	}
 
 	if *paletteMaxColorsAuto {
@@ -69,6 +204,42 @@ func run() error {
 		opts = append(opts, countdown.WithoutLeadingZeros())
 	}
 
+	if *expiredHold {
+		opts = append(opts, countdown.WithExpiredHold())
+	}
+
+	if *countUp {
+		opts = append(opts, countdown.WithCountUp())
+	}
+
+	if *flipClock {
+		opts = append(opts, countdown.WithFlipClock())
+	}
+
+	if *blinkSeparator {
+		opts = append(opts, countdown.WithBlinkingSeparator())
+	}
+
+	if *timerPosition != "" {
+		x, y, err := parsePoint(*timerPosition)
+		if err != nil {
+			return fmt.Errorf("failed to parse timer position: %v", err)
+		}
+		opts = append(opts, countdown.WithTimerPosition(x, y))
+	}
+
+	if *progressBar {
+		opts = append(opts, countdown.WithProgressBar(*progressAnchor))
+	}
+
+	if *progressRing {
+		opts = append(opts, countdown.WithProgressRing(*progressAnchor))
+	}
+
+	if *rtl {
+		opts = append(opts, countdown.WithRTL())
+	}
+
 	gen, err := countdown.NewGenerator(opts...)
 	if err != nil {
 		return fmt.Errorf("failed to create generator: %v", err)
@@ -98,6 +269,24 @@ func run() error {
 	return nil
 }
 
+// parsePoint parses "x,y" pair of integers
+func parsePoint(s string) (int, int, error) {
+	xs, ys, ok := strings.Cut(s, ",")
+	if !ok {
+		return 0, 0, fmt.Errorf("expected x,y, got %q", s)
+	}
+
+	x, err := strconv.Atoi(strings.TrimSpace(xs))
+	if err != nil {
+		return 0, 0, err
+	}
+	y, err := strconv.Atoi(strings.TrimSpace(ys))
+	if err != nil {
+		return 0, 0, err
+	}
+	return x, y, nil
+}
+
 func humanizeBytes(size int64) string {
 	units := []string{"B", "KB", "MB", "GB", "TB", "PB", "EB", "ZB", "YB"}
 	unit := 0
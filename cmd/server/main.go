@@ -2,19 +2,33 @@ package main
 
 import (
 	"bytes"
+	"flag"
 	"fmt"
+	"image"
 	"log"
 	"net/http"
+	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/chuhlomin/countdown"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/opentype"
 )
 
 const bind = ":8191"
 
 func main() {
-	http.Handle("/", HandlerGif())
+	fallbackFonts := flag.String("ff", "", "comma-separated paths to fallback fonts for missing glyphs, tried before Go Bold")
+	flag.Parse()
+
+	fonts, err := loadFonts(*fallbackFonts)
+	if err != nil {
+		log.Fatalf("failed to load fallback fonts: %v", err)
+	}
+
+	http.Handle("/", HandlerGif(fonts))
 
 	log.Println("Starting server on " + bind)
 	if err := http.ListenAndServe(bind, nil); err != nil {
@@ -22,7 +36,28 @@ func main() {
 	}
 }
 
-func HandlerGif() http.HandlerFunc {
+// loadFonts reads fonts of the comma-separated paths followed by Go Bold,
+// which has glyphs for labels of all bundled locales
+func loadFonts(paths string) ([][]byte, error) {
+	var fonts [][]byte
+	if paths != "" {
+		for _, path := range strings.Split(paths, ",") {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := opentype.Parse(data); err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+			}
+			fonts = append(fonts, data)
+		}
+	}
+	return append(fonts, gobold.TTF), nil
+}
+
+// HandlerGif returns a handler generating countdown GIFs,
+// runes missing in fonts are drawn with fallback fonts
+func HandlerGif(fallbackFonts [][]byte) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
 		opts, err := processRequest(req)
 		if err != nil {
@@ -30,9 +65,15 @@ func HandlerGif() http.HandlerFunc {
 			return
 		}
 
+		for _, data := range fallbackFonts {
+			opts = append(opts, countdown.WithFallbackFontOpenTypeData(data))
+		}
+
+		// options are validated by the generator,
+		// fallback fonts of the server are checked on start
 		gen, err := countdown.NewGenerator(opts...)
 		if err != nil {
-			http.Error(w, fmt.Sprintf("failed to create generator: %v", err), http.StatusInternalServerError)
+			http.Error(w, fmt.Sprintf("failed to create generator: %v", err), http.StatusBadRequest)
 			return
 		}
 
@@ -51,32 +92,134 @@ func HandlerGif() http.HandlerFunc {
 }
 
 var parseMap = map[string]func(string) (interface{}, error){
-	"from": func(s string) (interface{}, error) { return time.ParseDuration(s) },
-	"max":  func(s string) (interface{}, error) { return strconv.Atoi(s) },
-	"w":    func(s string) (interface{}, error) { return strconv.Atoi(s) },
-	"h":    func(s string) (interface{}, error) { return strconv.Atoi(s) },
-	"cy":   func(s string) (interface{}, error) { return strconv.Atoi(s) },
-	"pm":   func(s string) (interface{}, error) { return strconv.Atoi(s) },
-	"t":    func(s string) (interface{}, error) { return strconv.Atoi(s) },
+	"from":  func(s string) (interface{}, error) { return time.ParseDuration(s) },
+	"max":   func(s string) (interface{}, error) { return strconv.Atoi(s) },
+	"w":     func(s string) (interface{}, error) { return strconv.Atoi(s) },
+	"h":     func(s string) (interface{}, error) { return strconv.Atoi(s) },
+	"cy":    func(s string) (interface{}, error) { return strconv.Atoi(s) },
+	"pm":    func(s string) (interface{}, error) { return strconv.Atoi(s) },
+	"t":     func(s string) (interface{}, error) { return strconv.Atoi(s) },
+	"st":    func(s string) (interface{}, error) { return strconv.Atoi(s) },
+	"fps":   func(s string) (interface{}, error) { return strconv.Atoi(s) },
+	"fd":    func(s string) (interface{}, error) { return strconv.Atoi(s) },
+	"cardr": func(s string) (interface{}, error) { return strconv.Atoi(s) },
+	"cardp": func(s string) (interface{}, error) { return strconv.Atoi(s) },
+	"tf":    func(s string) (interface{}, error) { return strconv.Atoi(s) },
+	"td":    func(s string) (interface{}, error) { return strconv.ParseFloat(s, 64) },
+	"lsp":   func(s string) (interface{}, error) { return strconv.Atoi(s) },
+	"sw":    func(s string) (interface{}, error) { return strconv.Atoi(s) },
+	"shb":   func(s string) (interface{}, error) { return strconv.Atoi(s) },
+	"sho":   parsePoint,
+	"tpad":  func(s string) (interface{}, error) { return strconv.Atoi(s) },
+	"tpos":  parsePoint,
+	"ttlw":  func(s string) (interface{}, error) { return strconv.Atoi(s) },
+	"subw":  func(s string) (interface{}, error) { return strconv.Atoi(s) },
+	"pt":    func(s string) (interface{}, error) { return strconv.Atoi(s) },
+	"ps":    func(s string) (interface{}, error) { return strconv.Atoi(s) },
+	"po":    parsePoint,
 }
 
 var applyMap = map[string]func(interface{}) countdown.Option{
-	"bg":   func(v interface{}) countdown.Option { return countdown.WithBackgroundColor(v.(string)) },
-	"c":    func(v interface{}) countdown.Option { return countdown.WithTextColor(v.(string)) },
-	"from": func(v interface{}) countdown.Option { return countdown.WithTimeFrom(v.(time.Duration)) },
-	"max":  func(v interface{}) countdown.Option { return countdown.WithMaxFrames(v.(int)) },
-	"w":    func(v interface{}) countdown.Option { return countdown.WithWidth(v.(int)) },
-	"h":    func(v interface{}) countdown.Option { return countdown.WithHeight(v.(int)) },
-	"cy":   func(v interface{}) countdown.Option { return countdown.WithColonCompensation(v.(int)) },
-	"ca":   func(v interface{}) countdown.Option { return countdown.WithColonCompensationAuto() },
-	"pm":   func(v interface{}) countdown.Option { return countdown.WithPaletteMaxColors(v.(int)) },
-	"t":    func(v interface{}) countdown.Option { return countdown.WithTargetTime(v.(int)) },
-	"no0":  func(v interface{}) countdown.Option { return countdown.WithoutLeadingZeros() },
+	"bg":     func(v interface{}) countdown.Option { return countdown.WithBackgroundColor(v.(string)) },
+	"c":      func(v interface{}) countdown.Option { return countdown.WithTextColor(v.(string)) },
+	"cr":     func(v interface{}) countdown.Option { return countdown.WithColorRules(v.(string)) },
+	"bgg":    func(v interface{}) countdown.Option { return countdown.WithBackgroundGradient(v.(string)) },
+	"cg":     func(v interface{}) countdown.Option { return countdown.WithTextGradient(v.(string)) },
+	"sw":     func(v interface{}) countdown.Option { return countdown.WithStrokeWidth(v.(int)) },
+	"sc":     func(v interface{}) countdown.Option { return countdown.WithStrokeColor(v.(string)) },
+	"shc":    func(v interface{}) countdown.Option { return countdown.WithShadowColor(v.(string)) },
+	"shb":    func(v interface{}) countdown.Option { return countdown.WithShadowBlur(v.(int)) },
+	"sho":    withShadowOffset,
+	"ta":     func(v interface{}) countdown.Option { return countdown.WithTimerAnchor(v.(string)) },
+	"tpad":   func(v interface{}) countdown.Option { return countdown.WithTimerPadding(v.(int)) },
+	"tpos":   withTimerPosition,
+	"ttl":    func(v interface{}) countdown.Option { return countdown.WithTitle(v.(string)) },
+	"ttlc":   func(v interface{}) countdown.Option { return countdown.WithTitleColor(v.(string)) },
+	"ttlw":   func(v interface{}) countdown.Option { return countdown.WithTitleWidth(v.(int)) },
+	"sub":    func(v interface{}) countdown.Option { return countdown.WithSubtitle(v.(string)) },
+	"subc":   func(v interface{}) countdown.Option { return countdown.WithSubtitleColor(v.(string)) },
+	"subw":   func(v interface{}) countdown.Option { return countdown.WithSubtitleWidth(v.(int)) },
+	"pb":     func(v interface{}) countdown.Option { return countdown.WithProgressBar(v.(string)) },
+	"pr":     func(v interface{}) countdown.Option { return countdown.WithProgressRing(v.(string)) },
+	"pc":     func(v interface{}) countdown.Option { return countdown.WithProgressColor(v.(string)) },
+	"ptc":    func(v interface{}) countdown.Option { return countdown.WithProgressTrackColor(v.(string)) },
+	"pt":     func(v interface{}) countdown.Option { return countdown.WithProgressThickness(v.(int)) },
+	"ps":     func(v interface{}) countdown.Option { return countdown.WithProgressSize(v.(int)) },
+	"po":     withProgressOffset,
+	"from":   func(v interface{}) countdown.Option { return countdown.WithTimeFrom(v.(time.Duration)) },
+	"max":    func(v interface{}) countdown.Option { return countdown.WithMaxFrames(v.(int)) },
+	"w":      func(v interface{}) countdown.Option { return countdown.WithWidth(v.(int)) },
+	"h":      func(v interface{}) countdown.Option { return countdown.WithHeight(v.(int)) },
+	"cy":     func(v interface{}) countdown.Option { return countdown.WithColonCompensation(v.(int)) },
+	"ca":     func(v interface{}) countdown.Option { return countdown.WithColonCompensationAuto() },
+	"pm":     func(v interface{}) countdown.Option { return countdown.WithPaletteMaxColors(v.(int)) },
+	"t":      func(v interface{}) countdown.Option { return countdown.WithTargetTime(v.(int)) },
+	"no0":    func(v interface{}) countdown.Option { return countdown.WithoutLeadingZeros() },
+	"u":      func(v interface{}) countdown.Option { return countdown.WithUnits(v.(string)) },
+	"l":      func(v interface{}) countdown.Option { return countdown.WithLabels(v.(string)) },
+	"lc":     func(v interface{}) countdown.Option { return countdown.WithLabelColor(v.(string)) },
+	"lsp":    func(v interface{}) countdown.Option { return countdown.WithLabelSpacing(v.(int)) },
+	"locale": func(v interface{}) countdown.Option { return countdown.WithLocale(v.(string)) },
+	"ns":     func(v interface{}) countdown.Option { return countdown.WithNumberingSystem(v.(string)) },
+	"la":     func(v interface{}) countdown.Option { return countdown.WithLabelAlign(v.(string)) },
+	"rtl":    func(v interface{}) countdown.Option { return countdown.WithRTL() },
+	"fmt":    func(v interface{}) countdown.Option { return countdown.WithFormat(v.(string)) },
+	"up":     func(v interface{}) countdown.Option { return countdown.WithCountUp() },
+	"st":     func(v interface{}) countdown.Option { return countdown.WithStartTime(v.(int)) },
+	"et":     func(v interface{}) countdown.Option { return countdown.WithExpiredText(v.(string)) },
+	"fps":    func(v interface{}) countdown.Option { return countdown.WithFPS(v.(int)) },
+	"fd":     func(v interface{}) countdown.Option { return countdown.WithFractionDigits(v.(int)) },
+	"flip":   func(v interface{}) countdown.Option { return countdown.WithFlipClock() },
+	"cardc":  func(v interface{}) countdown.Option { return countdown.WithCardColor(v.(string)) },
+	"cardr":  func(v interface{}) countdown.Option { return countdown.WithCardRadius(v.(int)) },
+	"cardp":  func(v interface{}) countdown.Option { return countdown.WithCardPadding(v.(int)) },
+	"tf":     func(v interface{}) countdown.Option { return countdown.WithTransitionFrames(v.(int)) },
+	"tr":     func(v interface{}) countdown.Option { return countdown.WithTransition(v.(string)) },
+	"td":     func(v interface{}) countdown.Option { return countdown.WithTransitionDuration(v.(float64)) },
+	"blink":  func(v interface{}) countdown.Option { return countdown.WithBlinkingSeparator() },
+	"sdc":    func(v interface{}) countdown.Option { return countdown.WithSeparatorDimColor(v.(string)) },
+	"sep":    func(v interface{}) countdown.Option { return countdown.WithSeparators(v.(string)) },
+	"sepy":   func(v interface{}) countdown.Option { return countdown.WithSeparatorOffsets(v.(string)) },
+}
+
+// parsePoint parses "x,y" pair of integers
+func parsePoint(s string) (interface{}, error) {
+	xs, ys, ok := strings.Cut(s, ",")
+	if !ok {
+		return nil, fmt.Errorf("expected x,y, got %q", s)
+	}
+
+	x, err := strconv.Atoi(xs)
+	if err != nil {
+		return nil, err
+	}
+	y, err := strconv.Atoi(ys)
+	if err != nil {
+		return nil, err
+	}
+	return image.Point{X: x, Y: y}, nil
+}
+
+func withShadowOffset(v interface{}) countdown.Option {
+	p := v.(image.Point)
+	return countdown.WithShadowOffset(p.X, p.Y)
+}
+
+func withTimerPosition(v interface{}) countdown.Option {
+	p := v.(image.Point)
+	return countdown.WithTimerPosition(p.X, p.Y)
+}
+
+func withProgressOffset(v interface{}) countdown.Option {
+	p := v.(image.Point)
+	return countdown.WithProgressOffset(p.X, p.Y)
 }
 
 func processRequest(req *http.Request) ([]countdown.Option, error) {
 	var (
-		opts []countdown.Option
+		// images embedded in emails can't be updated after the target time,
+		// so keep serving the expired frame instead of an error
+		opts = []countdown.Option{countdown.WithExpiredHold()}
 		err  error
 	)
 
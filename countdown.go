@@ -9,12 +9,14 @@ import (
 	_ "image/jpeg"
 	_ "image/png"
 	"io"
+	"slices"
 	"sort"
 	"strings"
 	"time"
 
 	"golang.org/x/image/font"
 	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/font/opentype"
 	"golang.org/x/image/math/fixed"
 )
 
@@ -33,6 +35,127 @@ type Generator struct {
 	PaletteMaxColorsAuto   bool
 	ColonCompoensationAuto bool
 	NoLeadingZeros         bool
+	Units                  Unit
+	Labels                 map[Unit][]string
+	LabelFontFace          font.Face
+	LabelFontSize          float64
+	LabelColor             color.Color
+	LabelSpacing           int
+	Locale                 string
+	PluralRule             PluralRule
+	ZeroDigit              rune
+	RTL                    bool
+	LabelAlign             Align
+	Format                 string
+	CountUp                bool
+	ExpiredText            string
+	ExpiredBackgroundImage *image.Image
+	ExpiredHold            bool
+	FPS                    int
+	FractionDigits         int
+	FlipClock              bool
+	CardColor              color.Color
+	CardRadius             int
+	CardPadding            int
+	Transition             Transition
+	TransitionFrames       int
+	TransitionDuration     float64
+	BlinkSeparator         bool
+	SeparatorDimColor      color.Color
+	Separators             map[Unit]Separator
+	ColorRules             []ColorRule
+	StrokeWidth            int
+	StrokeColor            color.Color
+	ShadowColor            color.Color
+	ShadowOffsetX          int
+	ShadowOffsetY          int
+	ShadowBlur             int
+	BackgroundGradient     *Gradient
+	TextGradient           *Gradient
+	BackgroundFit          Fit
+	BackgroundAnchor       Anchor
+	BackgroundOffsetX      int
+	BackgroundOffsetY      int
+	BackgroundAnimation    *Animation
+	TimerAnchor            Anchor
+	TimerPadding           int
+	TimerPosition          *image.Point
+	Font                   *opentype.Font
+	FontAutoFit            bool
+	FontAutoFitMargin      int
+	Title                  TextBlock
+	Subtitle               TextBlock
+	Layers                 []Layer
+	Progress               Progress
+	DigitSprites           map[rune]image.Image
+	FallbackFonts          []*opentype.Font
+}
+
+// ColorRule overrides TextColor of the timer, the last matching rule wins.
+type ColorRule struct {
+	Units Unit          // fields of the units, zero for the whole timer including separators
+	Below time.Duration // applies when the timer value is below, zero for any value
+	Color color.Color
+}
+
+// Separator replaces the colon after the unit in the default format.
+type Separator struct {
+	Text   string      // text instead of the colon, e.g. "d "
+	Image  image.Image // image drawn instead of the text, if set
+	Offset *int        // vertical offset in pixels, positive moves it up, replaces ColonCompensation if set
+}
+
+// Transition is an animation of digits changing between frames.
+type Transition int
+
+const (
+	TransitionNone Transition = iota
+	// TransitionFlip folds the top half of the card down, like split-flap displays
+	TransitionFlip
+	// TransitionSlide moves the previous digit up and out, the next one in from below
+	TransitionSlide
+	// TransitionFade cross-fades the previous digit into the next one
+	TransitionFade
+	// TransitionRoll rotates digits on a drum, like odometers
+	TransitionRoll
+)
+
+// defaultCountUpSeconds limits the animation length when counting up,
+// as there is no natural end, unless MaxFrames is set
+const defaultCountUpSeconds = 60
+
+// Align is a horizontal alignment relative to the reading direction.
+type Align int
+
+const (
+	AlignCenter Align = iota
+	AlignStart
+	AlignEnd
+)
+
+// Unit is a set of time units shown by the countdown.
+type Unit int
+
+const (
+	UnitDays Unit = 1 << iota
+	UnitHours
+	UnitMinutes
+	UnitSeconds
+
+	// UnitFraction is a fraction of a second,
+	// the number of digits defines the precision: tenths, hundredths, etc.
+	UnitFraction
+)
+
+// unitDurations lists all units from the largest to the smallest
+var unitDurations = []struct {
+	unit     Unit
+	duration time.Duration
+}{
+	{UnitDays, 24 * time.Hour},
+	{UnitHours, time.Hour},
+	{UnitMinutes, time.Minute},
+	{UnitSeconds, time.Second},
 }
 
 func NewGenerator(opts ...Option) (*Generator, error) {
@@ -43,6 +166,25 @@ func NewGenerator(opts ...Option) (*Generator, error) {
 		FontFace:        basicfont.Face7x13,
 		BackgroundColor: color.Black,
 		TextColor:       color.White,
+		LabelFontSize:   16,
+		LabelFontFace:   basicfont.Face7x13,
+		LabelSpacing:    8,
+		Title:           TextBlock{FontFace: basicfont.Face7x13, FontSize: 16, Spacing: 8},
+		Subtitle:        TextBlock{FontFace: basicfont.Face7x13, FontSize: 16, Spacing: 8},
+		PluralRule:      PluralRules["en"],
+		ZeroDigit:       '0',
+		FPS:             1,
+		CardColor:       color.RGBA{0x33, 0x33, 0x33, 0xff},
+		CardRadius:      6,
+		CardPadding:     8,
+		StrokeColor:     color.Black,
+		ShadowOffsetX:   2,
+		ShadowOffsetY:   2,
+		ShadowBlur:      2,
+		Progress:        Progress{Thickness: 8},
+
+		TransitionFrames:   4,
+		TransitionDuration: 0.3,
 	}
 	for _, opt := range opts {
 		err := opt(g)
@@ -50,9 +192,92 @@ func NewGenerator(opts ...Option) (*Generator, error) {
 			return nil, err
 		}
 	}
+
+	if g.TimeFrom < 0 && !g.CountUp {
+		if !g.expiredFrame() {
+			return nil, ErrTargetTimeInPast
+		}
+		// render a single expired frame instead
+		g.TimeFrom = 0
+	}
+
+	if g.TimeFrom < 0 && g.CountUp {
+		// counting up starts in the future
+		return nil, ErrStartTimeInFuture
+	}
+
+	// background images are resampled once rather than on every frame
+	fit := func(img *image.Image) *image.Image {
+		if img == nil {
+			return nil
+		}
+		fitted := fitImage(*img, g.Width, g.Height, g.BackgroundFit, g.BackgroundAnchor, image.Pt(g.BackgroundOffsetX, g.BackgroundOffsetY))
+		return &fitted
+	}
+	g.BackgroundImage = fit(g.BackgroundImage)
+	g.ExpiredBackgroundImage = fit(g.ExpiredBackgroundImage)
+	if a := g.BackgroundAnimation; a != nil {
+		fitted := &Animation{Delays: a.Delays}
+		for i := range a.Frames {
+			fitted.Frames = append(fitted.Frames, *fit(&a.Frames[i]))
+		}
+		g.BackgroundAnimation = fitted
+	}
+
+	if g.FlipClock && g.Transition == TransitionNone {
+		g.Transition = TransitionFlip
+	}
+
+	if g.Labels == nil && g.Locale != "" {
+		labels, _ := lookupLocale(LocaleLabels, g.Locale)
+		if err := WithLabels(labels)(g); err != nil {
+			return nil, err
+		}
+	}
+
+	// fonts are followed by fallback fonts of the same size
+	faces := []struct {
+		face *font.Face
+		size float64
+	}{
+		{&g.FontFace, g.FontSize},
+		{&g.LabelFontFace, g.LabelFontSize},
+		{&g.Title.FontFace, g.Title.FontSize},
+		{&g.Subtitle.FontFace, g.Subtitle.FontSize},
+	}
+	for _, f := range faces {
+		var err error
+		*f.face, err = g.withFallback(*f.face, f.size)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// labels take space too, so the font is fitted when they are known
+	if g.FontAutoFit {
+		if err := g.fitFontSize(); err != nil {
+			return nil, err
+		}
+	}
+
+	// sprites replace glyphs of the font, which draws the rest of the text
+	if g.DigitSprites != nil {
+		g.FontFace = &SpriteFace{Images: g.DigitSprites, Fallback: g.FontFace}
+	}
+
+	if err := g.checkGlyphs(); err != nil {
+		return nil, err
+	}
+
 	return g, nil
 }
 
+// expiredFrame reports whether the final frame is configured,
+// so the countdown may start expired
+func (g *Generator) expiredFrame() bool {
+	return g.ExpiredHold || g.ExpiredText != "" || g.ExpiredBackgroundImage != nil
+}
+
 func (g *Generator) Write(w io.Writer) error {
 	var count int
 
@@ -63,28 +288,149 @@ func (g *Generator) Write(w io.Writer) error {
 		Face: g.FontFace,
 	}
 
+	labelColor := g.LabelColor
+	if labelColor == nil {
+		labelColor = g.TextColor
+	}
+	labelDrawer := &font.Drawer{
+		Src:  image.NewUniform(labelColor),
+		Face: g.LabelFontFace,
+	}
+
 	if g.ColonCompoensationAuto {
 		// for most fonts, the colon is placed at the bottom of the cell, and has x-height height
 		// to center it vertically, we need to move it up by (capHeight - xHeight) / 2
 		g.ColonCompensation = (g.FontFace.Metrics().CapHeight.Ceil() - g.FontFace.Metrics().XHeight.Ceil()) / 2
 	}
 
-	for g.TimeFrom >= 0 && (g.MaxFrames == 0 || count < g.MaxFrames) {
-		frame, err := g.renderFrame(fontDrawer)
+	var format []token
+	if g.Format != "" {
+		var err error
+		format, err = parseFormat(g.Format)
+		if err != nil {
+			return fmt.Errorf("failed to parse format: %v", err)
+		}
+	}
+
+	maxFrames := g.MaxFrames
+	if g.CountUp && maxFrames == 0 {
+		maxFrames = defaultCountUpSeconds * g.FPS
+	}
+
+	var delays []int
+
+	// elapsed is the time from the start of the animation in 100ths of a second,
+	// separators are blinked off in the second half of every second
+	var elapsed int
+	var separatorsOff []bool
+
+	timeFrom := g.TimeFrom
+	lastValue := g.lastValue()
+	progress := func(value time.Duration) float64 {
+		switch {
+		case g.CountUp && lastValue > timeFrom:
+			return float64(value-timeFrom) / float64(lastValue-timeFrom)
+		case g.CountUp:
+			return 1
+		case timeFrom > 0:
+			return float64(value) / float64(timeFrom)
+		default:
+			return 0
+		}
+	}
+
+	var emit func(s frameState, delay int) error
+	emit = func(s frameState, delay int) error {
+		expired := !g.CountUp && s.value == 0
+		// countdown doesn't blink after it expires
+		blink := g.BlinkSeparator && !expired
+		pos := elapsed % 100
+
+		// split the frame where the separator is blinked off
+		// or the background animation moves to the next frame
+		split := delay
+		if blink && pos < 50 {
+			split = min(split, 50-pos)
+		}
+		if g.BackgroundAnimation != nil && !(expired && g.ExpiredBackgroundImage != nil) {
+			_, left := g.BackgroundAnimation.frameAt(elapsed)
+			split = min(split, left)
+		}
+		if split < delay {
+			if err := emit(s, split); err != nil {
+				return err
+			}
+			return emit(s, delay-split)
+		}
+
+		s.separatorOff = blink && pos >= 50
+		s.elapsed = elapsed
+		s.progress = progress(s.value)
+		frame, err := g.renderFrame(fontDrawer, labelDrawer, format, s)
 		if err != nil {
 			return fmt.Errorf("failed to render frame: %v", err)
 		}
 
 		frames = append(frames, frame)
+		delays = append(delays, delay)
+		elapsed += delay
+		separatorsOff = append(separatorsOff, s.separatorOff)
+		return nil
+	}
+
+	prevValue := g.TimeFrom
+	for g.TimeFrom >= 0 && (maxFrames == 0 || count < maxFrames) {
+		delay := frameDelay(count, g.FPS)
 
-		// decrease timeFrom by 1 second
-		g.TimeFrom = g.TimeFrom - time.Second
+		// animate digits changing from the previous value
+		if count > 0 && g.Transition != TransitionNone && g.changed(format, prevValue, g.TimeFrom) {
+			steps := transitionDelays(delay, g.TransitionFrames, g.TransitionDuration)
+			for i, stepDelay := range steps {
+				err := emit(frameState{
+					value:      g.TimeFrom,
+					prevValue:  prevValue,
+					transition: float64(i+1) / float64(len(steps)+1),
+				}, stepDelay)
+				if err != nil {
+					return err
+				}
+				delay -= stepDelay
+			}
+		}
+
+		if err := emit(frameState{value: g.TimeFrom, transition: 1}, delay); err != nil {
+			return err
+		}
+
+		prevValue = g.TimeFrom
 		count++
+
+		// step is calculated from the frame number rather than accumulated,
+		// so rounding errors don't add up for frame rates like 3 fps
+		step := time.Duration(count) * time.Second / time.Duration(g.FPS)
+		if g.CountUp {
+			g.TimeFrom = timeFrom + step
+		} else {
+			g.TimeFrom = timeFrom - step
+			// time left is less than a step, e.g. for the target time,
+			// so the last frame shows zero
+			if g.TimeFrom < 0 && prevValue > 0 {
+				g.TimeFrom = 0
+			}
+		}
+	}
+
+	// the animation stops on the last frame, so it should show the separator,
+	// the second half of a second is split into several frames by background animation
+	for n := len(frames); n > 1 && separatorsOff[n-1]; n-- {
+		frames = frames[:n-1]
+		delays[n-2] += delays[n-1]
+		delays = delays[:n-1]
 	}
 
 	gw := &gif.GIF{
 		Image:     make([]*image.Paletted, len(frames)),
-		Delay:     make([]int, len(frames)),
+		Delay:     delays,
 		LoopCount: -1,
 	}
 
@@ -93,7 +439,6 @@ func (g *Generator) Write(w io.Writer) error {
 	for i, frame := range frames {
 		gw.Image[i] = image.NewPaletted(frame.Bounds(), palette)
 		draw.FloydSteinberg.Draw(gw.Image[i], frame.Bounds(), frame, image.Point{})
-		gw.Delay[i] = 100
 	}
 
 	if err := gif.EncodeAll(w, gw); err != nil {
@@ -103,88 +448,529 @@ func (g *Generator) Write(w io.Writer) error {
 	return nil
 }
 
-func (g *Generator) renderFrame(d *font.Drawer) (image.Image, error) {
+// frameDelay returns delay of the i-th frame in 100ths of a second.
+// GIF delays can't be fractional, so for frame rates like 3 fps
+// delays alternate (33, 33, 34) to keep every second exactly 100.
+func frameDelay(i, fps int) int {
+	return (i+1)*100/fps - i*100/fps
+}
+
+// frameState is the value of the timer in a frame
+type frameState struct {
+	value time.Duration
+
+	// prevValue is the value of the previous frame,
+	// transition is the progress of changing digits from it, 1 when finished
+	prevValue  time.Duration
+	transition float64
+
+	// separatorOff is set in the second half of a second
+	// to dim or hide blinking separators
+	separatorOff bool
+
+	// elapsed is the time from the start of the animation in 100ths of a second
+	elapsed int
+
+	// progress is the fraction of the countdown left, see FrameState
+	progress float64
+}
+
+func (g *Generator) renderFrame(d, ld *font.Drawer, format []token, s frameState) (image.Image, error) {
 	// create image 600×400 pixels with black background and white text
 	img := image.NewRGBA(image.Rect(0, 0, g.Width, g.Height))
-	draw.Draw(img, img.Bounds(), &image.Uniform{g.BackgroundColor}, image.Point{}, draw.Src)
+	var background image.Image = &image.Uniform{g.BackgroundColor}
+	if g.BackgroundGradient != nil {
+		background = g.BackgroundGradient.In(img.Bounds())
+	}
+	draw.Draw(img, img.Bounds(), background, image.Point{}, draw.Src)
+
+	expired := !g.CountUp && s.value == 0
 
-	if g.BackgroundImage != nil {
-		draw.Draw(img, img.Bounds(), *g.BackgroundImage, image.Point{}, draw.Over)
+	backgroundImage := g.BackgroundImage
+	if g.BackgroundAnimation != nil {
+		i, _ := g.BackgroundAnimation.frameAt(s.elapsed)
+		backgroundImage = &g.BackgroundAnimation.Frames[i]
+	}
+	if expired && g.ExpiredBackgroundImage != nil {
+		backgroundImage = g.ExpiredBackgroundImage
+	}
+	if backgroundImage != nil {
+		draw.Draw(img, img.Bounds(), *backgroundImage, image.Point{}, draw.Over)
+	}
+
+	layers := g.Layers
+	if len(layers) == 0 {
+		layers = []Layer{CountdownLayer}
+		if g.Progress.Style != ProgressNone {
+			layers = []Layer{&g.Progress, CountdownLayer}
+		}
 	}
 
-	d.Dst = img
+	state := FrameState{
+		Value:   s.value,
+		Elapsed: time.Duration(s.elapsed) * 10 * time.Millisecond,
+		Expired: expired,
+
+		Progress: s.progress,
+
+		g:      g,
+		frame:  s,
+		d:      d,
+		ld:     ld,
+		format: format,
+	}
+	for _, layer := range layers {
+		layer.Draw(img, state)
+	}
+
+	return img, nil
+}
+
+// drawTimer draws the timer with labels, title and subtitle, or the expired text on dst
+func (g *Generator) drawTimer(dst draw.Image, d, ld *font.Drawer, format []token, s frameState) {
+	d.Dst = dst
+	ld.Dst = dst
+
+	if g.textEffects() {
+		// text is drawn on a separate layer to outline it and cast a shadow,
+		// which is composed with layers below when the timer is ready
+		text := image.NewRGBA(dst.Bounds())
+		d.Dst = text
+		ld.Dst = text
+		defer g.drawTextEffects(dst, text)
+	}
+
+	capHeight := g.FontFace.Metrics().CapHeight
+
+	if !g.CountUp && s.value == 0 && g.ExpiredText != "" {
+		width := d.MeasureString(g.ExpiredText)
+		dot, lines := g.placeTimer(dst.Bounds(), width, fixed.I(capHeight.Ceil()))
+		dot.Y += fixed.I(capHeight.Ceil())
+		g.drawTextLines(d.Dst, lines)
+
+		bounds := image.Rect(dot.X.Floor(), (dot.Y - capHeight).Floor(), (dot.X + width).Ceil(), dot.Y.Ceil())
 
+		d := withSource(d, g.textSource(0, s.value, bounds))
+		d.Dot = dot
+		drawString(d, g.ExpiredText)
+		return
+	}
+
+	l := g.layoutTimer(d, ld, g.parts(format, s.value), dst.Bounds())
+
+	var prev *timerLayout
+	if s.transition < 1 {
+		p := g.layoutTimer(d, ld, g.parts(format, s.prevValue), dst.Bounds())
+		if l.aligned(p) {
+			prev = &p
+		}
+	}
+
+	bounds := l.textBounds(capHeight)
+	for i, c := range l.cells {
+		d := withSource(d, g.textSource(c.unit, s.value, bounds))
+		if c.separator && s.separatorOff {
+			if g.SeparatorDimColor != nil && c.img == nil {
+				g.drawCell(withSource(d, image.NewUniform(g.SeparatorDimColor)), c, l.y)
+			}
+			continue
+		}
+		if prev != nil && c.digit && prev.cells[i].text != c.text {
+			g.drawTransition(d, prev.cells[i], c, l.y, s.transition)
+			continue
+		}
+		g.drawCell(d, c, l.y)
+	}
+
+	for _, label := range l.labels {
+		g.drawLabel(ld, label.text, label.x0, label.x1, l.labelY)
+	}
+	g.drawTextLines(d.Dst, l.lines)
+}
+
+// textSource returns the fill of fields of the unit, or of literal text if unit is zero,
+// for the timer value: the last matching color rule, the text gradient spanning bounds,
+// or the text color
+func (g *Generator) textSource(unit Unit, value time.Duration, bounds image.Rectangle) image.Image {
+	var src image.Image = image.NewUniform(g.TextColor)
+	if g.TextGradient != nil {
+		src = g.TextGradient.In(bounds)
+	}
+
+	for _, r := range g.ColorRules {
+		if r.Units != 0 && r.Units&unit == 0 {
+			continue
+		}
+		if r.Below != 0 && value >= r.Below {
+			continue
+		}
+		src = image.NewUniform(r.Color)
+	}
+	return src
+}
+
+// withSource returns a copy of the drawer with the text source src
+func withSource(d *font.Drawer, src image.Image) *font.Drawer {
+	cd := *d
+	cd.Src = src
+	return &cd
+}
+
+// parts returns formatted parts of the timer in the order they are drawn
+func (g *Generator) parts(format []token, value time.Duration) []segment {
+	var parts []segment
+	if format != nil {
+		parts = formatTemplate(value, format)
+	} else {
+		fractionDigits := g.FractionDigits
+		if g.CountUp {
+			fractionDigits = 0
+		}
+		parts = formatTime(value, g.Units, g.NoLeadingZeros, fractionDigits)
+	}
+	if g.RTL {
+		// parts go from right to left, but digits within each part keep their order
+		slices.Reverse(parts)
+	}
+	return parts
+}
+
+// changed reports whether the timer text differs between two values
+func (g *Generator) changed(format []token, a, b time.Duration) bool {
+	return !slices.Equal(g.parts(format, a), g.parts(format, b))
+}
+
+// cell is a positioned piece of the timer: a single digit or literal text
+type cell struct {
+	text  string
+	x     fixed.Int26_6 // left edge
+	width fixed.Int26_6
+	dy    fixed.Int26_6 // vertical offset, e.g. colon compensation
+	digit bool          // digits are centered in cells of the same width
+	unit  Unit          // unit of the digit, zero for literal text
+
+	separator bool        // literal text between fields, e.g. ":"
+	img       image.Image // drawn instead of the text
+}
+
+// partLabel is a label under the part spanning from x0 to x1
+type partLabel struct {
+	text   string
+	x0, x1 fixed.Int26_6
+}
+
+type timerLayout struct {
+	cells  []cell
+	labels []partLabel
+	y      fixed.Int26_6 // baseline of the timer
+	labelY fixed.Int26_6 // top of the labels line
+	lines  []textLine    // title and subtitle
+}
+
+// aligned reports whether both layouts have the same cells at the same positions,
+// so digits can be animated cell by cell
+func (l timerLayout) aligned(other timerLayout) bool {
+	if len(l.cells) != len(other.cells) || l.y != other.y {
+		return false
+	}
+	for i, c := range l.cells {
+		o := other.cells[i]
+		if c.x != o.x || c.width != o.width || c.digit != o.digit {
+			return false
+		}
+	}
+	return true
+}
+
+// textBounds returns the box of the timer from the cap height to the baseline
+func (l timerLayout) textBounds(capHeight fixed.Int26_6) image.Rectangle {
+	first, last := l.cells[0], l.cells[len(l.cells)-1]
+	return image.Rect(first.x.Floor(), (l.y - capHeight).Floor(), (last.x + last.width).Ceil(), l.y.Ceil())
+}
+
+// placeBlock returns the top left corner of the block of size w×h aligned
+// to TimerAnchor inside of bounds with padding, or placed at TimerPosition
+func (g *Generator) placeBlock(bounds image.Rectangle, w, h fixed.Int26_6) fixed.Point26_6 {
+	area := bounds.Inset(g.TimerPadding)
+	if g.TimerPosition != nil {
+		area = image.Rectangle{Min: *g.TimerPosition, Max: *g.TimerPosition}
+	}
+
+	fx, fy := g.TimerAnchor.factors()
+	return fixed.Point26_6{
+		X: fixed.I(area.Min.X) + (fixed.I(area.Dx())-w)*fixed.Int26_6(fx)/2,
+		Y: fixed.I(area.Min.Y) + (fixed.I(area.Dy())-h)*fixed.Int26_6(fy)/2,
+	}
+}
+
+// cellSize returns the width of digit cells, the gap between them
+// and the padding of flip clock cards for the font of the drawer,
+// digit is the widest one
+func (g *Generator) cellSize(d *font.Drawer) (cellWidth, cellGap fixed.Int26_6, pad int, digit string) {
 	// not all fonts support tabular numbers,
 	// so to avoid text jumping, we need to split it into parts
-	// and draw each part separately, keeping ":" at the same position
-	parts := formatTime(g.TimeFrom, g.NoLeadingZeros)
+	// and draw each digit in a cell of the same width,
+	// keeping literal text like ":" at the same position
+	cellWidth, digit = findMaxDigitsWidth(d, g.ZeroDigit)
 
-	colonWidth := d.MeasureString(":")
-	maxDigitsWidth, digit := findMaxDigitsWidth(d)
-	totalWidth := d.MeasureString(strings.Repeat(":", len(parts)-1))
-	for _, part := range parts {
-		totalWidth += d.MeasureString(strings.Repeat(digit, len(part)))
+	// flip clock cards are wider than digits and separated by a gap
+	if g.FlipClock {
+		pad = g.CardPadding
+		cellWidth += fixed.I(2 * pad)
+		cellGap = fixed.I(g.cardGap())
 	}
+	return cellWidth, cellGap, pad, digit
+}
 
-	x := (fixed.I(img.Bounds().Dx()) - totalWidth) / 2
-	y := fixed.I(img.Bounds().Dy()+g.FontFace.Metrics().CapHeight.Ceil()) / 2
-	d.Dot = fixed.Point26_6{X: x, Y: y}
+// timerSize returns the size of the timer with labels
+func (g *Generator) timerSize(d *font.Drawer, parts []segment) (width fixed.Int26_6, height int) {
+	cellWidth, cellGap, pad, digit := g.cellSize(d)
 
 	for i, part := range parts {
-		d.Dot.X = x
+		switch {
+		case part.unit == 0:
+			width += g.literalCell(d, parts, i, 0).width
+		case g.FlipClock:
+			n := fixed.Int26_6(len(part.text))
+			width += n*cellWidth + (n-1)*cellGap
+		default:
+			width += d.MeasureString(strings.Repeat(digit, len(part.text)))
+		}
+	}
+
+	// labels are drawn as a second line of text,
+	// so the timer and labels are centered vertically as a single block
+	height = d.Face.Metrics().CapHeight.Ceil() + 2*pad
+	if len(g.Labels) > 0 {
+		height += g.LabelSpacing + g.LabelFontFace.Metrics().Height.Ceil()
+	}
+	return width, height
+}
 
-		if i > 0 {
-			d.Dot.Y -= fixed.I(g.ColonCompensation)
-			d.DrawString(":")
-			d.Dot.Y = y
-			x += colonWidth
+// layoutTimer positions parts of the timer in bounds
+func (g *Generator) layoutTimer(d, ld *font.Drawer, parts []segment, bounds image.Rectangle) timerLayout {
+	cellWidth, cellGap, pad, _ := g.cellSize(d)
+	totalWidth, blockHeight := g.timerSize(d, parts)
+	capHeight := g.FontFace.Metrics().CapHeight.Ceil()
+
+	top, lines := g.placeTimer(bounds, totalWidth, fixed.I(blockHeight))
+	x := top.X
+	y := top.Y + fixed.I(capHeight+pad)
+
+	l := timerLayout{y: y, labelY: y + fixed.I(pad+g.LabelSpacing), lines: lines}
+
+	for i, part := range parts {
+		if part.unit == 0 {
+			c := g.literalCell(d, parts, i, x)
+			l.cells = append(l.cells, c)
+			x += c.width
+			continue
 		}
 
-		for _, r := range part {
-			// align digits to the center of the "cell"
-			d.Dot.X = x + (maxDigitsWidth-d.MeasureString(string(r)))/2
-			x += maxDigitsWidth
-			d.DrawString(string(r))
+		partX := x
+		for j, r := range localizeDigits(part.text, g.ZeroDigit) {
+			if j > 0 {
+				x += cellGap
+			}
+			l.cells = append(l.cells, cell{text: string(r), x: x, width: cellWidth, digit: true, unit: part.unit})
+			x += cellWidth
+		}
+
+		if label := g.label(part); label != "" {
+			l.labels = append(l.labels, partLabel{text: label, x0: partX, x1: x})
 		}
 	}
 
-	return img, nil
+	return l
 }
 
-func formatTime(d time.Duration, noLeadingZeros bool) []string {
-	// format time as 00:00:00 if it's more than 1 hour
-	// or 00:00 if it's less than 1 hour
+// literalCell returns the cell of literal text parts[i] starting at x
+func (g *Generator) literalCell(d *font.Drawer, parts []segment, i int, x fixed.Int26_6) cell {
+	c := cell{text: parts[i].text, x: x}
 
-	h := int(d.Hours())
-	m := int(d.Minutes()) % 60
-	s := int(d.Seconds()) % 60
+	// only separators between fields are compensated,
+	// leading and trailing text stays on the baseline
+	if isSeparator(parts, i) {
+		c.separator = true
+		c.dy = fixed.I(g.ColonCompensation)
 
-	firstPartFormat := "%02d"
-	if noLeadingZeros {
-		firstPartFormat = "%d"
+		if s, ok := g.separator(parts, i); ok {
+			if s.Offset != nil {
+				c.dy = fixed.I(*s.Offset)
+			}
+			if s.Text != "" {
+				c.text = s.Text
+			}
+			c.img = s.Image
+		}
+	}
+
+	if c.img != nil {
+		c.width = fixed.I(c.img.Bounds().Dx())
+	} else {
+		c.width = d.MeasureString(c.text)
 	}
+	return c
+}
 
-	if h > 0 {
-		return []string{
-			fmt.Sprintf(firstPartFormat, h),
-			fmt.Sprintf("%02d", m),
-			fmt.Sprintf("%02d", s),
+// separator returns the separator configured for the literal parts[i],
+// it is looked up by the unit before it in the reading order
+func (g *Generator) separator(parts []segment, i int) (Separator, bool) {
+	if g.Format != "" {
+		// templates have their own separators
+		return Separator{}, false
+	}
+
+	before := parts[i-1].unit
+	if g.RTL {
+		before = parts[i+1].unit
+	}
+	s, ok := g.Separators[before]
+	return s, ok
+}
+
+// drawCell draws text of the cell on the baseline y
+func (g *Generator) drawCell(d *font.Drawer, c cell, y fixed.Int26_6) {
+	if c.img != nil {
+		// image is centered vertically on the digits
+		b := c.img.Bounds()
+		top := y - c.dy - (g.FontFace.Metrics().CapHeight+fixed.I(b.Dy()))/2
+		r := image.Rect(0, 0, b.Dx(), b.Dy()).Add(image.Pt(c.x.Round(), top.Round()))
+		draw.Draw(d.Dst, r, c.img, b.Min, draw.Over)
+		return
+	}
+
+	if g.FlipClock && c.digit {
+		img := g.renderCell(d, c, y)
+		draw.Draw(d.Dst, img.Bounds(), img, img.Bounds().Min, draw.Over)
+		return
+	}
+
+	d.Dot = fixed.Point26_6{X: c.x, Y: y - c.dy}
+	if c.digit {
+		// align digits to the center of the "cell"
+		d.Dot.X += (c.width - d.MeasureString(c.text)) / 2
+	}
+	drawString(d, c.text)
+}
+
+// isSeparator reports whether parts[i] is literal text between two fields
+func isSeparator(parts []segment, i int) bool {
+	return parts[i].unit == 0 &&
+		i > 0 && parts[i-1].unit != 0 &&
+		i < len(parts)-1 && parts[i+1].unit != 0
+}
+
+// label returns the label of the part in the plural form matching its value
+func (g *Generator) label(s segment) string {
+	forms := g.Labels[s.unit]
+	if len(forms) == 0 {
+		return ""
+	}
+	return forms[min(g.PluralRule.form(s.value), len(forms)-1)]
+}
+
+// drawLabel draws label under the part spanning from x0 to x1,
+// y is the top of the labels line
+func (g *Generator) drawLabel(ld *font.Drawer, label string, x0, x1, y fixed.Int26_6) {
+	width := ld.MeasureString(label)
+
+	align := g.LabelAlign
+	if g.RTL {
+		// start of the line is on the right
+		switch align {
+		case AlignStart:
+			align = AlignEnd
+		case AlignEnd:
+			align = AlignStart
+		}
+	}
+
+	ld.Dot.Y = y + g.LabelFontFace.Metrics().Ascent
+	switch align {
+	case AlignStart:
+		ld.Dot.X = x0
+	case AlignEnd:
+		ld.Dot.X = x1 - width
+	default:
+		ld.Dot.X = (x0 + x1 - width) / 2
+	}
+	drawString(ld, label)
+}
+
+// formatTime returns parts of the duration separated by colons,
+// if units is zero, they are picked automatically based on the duration.
+// In the last minute fraction of a second is added, if fractionDigits is set.
+func formatTime(d time.Duration, units Unit, noLeadingZeros bool, fractionDigits int) []segment {
+	if units == 0 {
+		units = autoUnits(d)
+	}
+
+	format := defaultFormat(units, noLeadingZeros)
+	if fractionDigits > 0 && d < time.Minute {
+		format = append(format, token{text: "."}, token{unit: UnitFraction, width: fractionDigits})
+	}
+	return formatTemplate(d, format)
+}
+
+// segment is a formatted part of the countdown text
+type segment struct {
+	unit  Unit // zero for literal text
+	value int
+	text  string
+}
+
+// splitTime breaks the duration down into the given units.
+// The largest unit absorbs the overflow of larger units that are not shown,
+// e.g. 2h5m split into minutes and seconds is 125 minutes and 0 seconds.
+func splitTime(d time.Duration, units Unit) []segment {
+	var segments []segment
+	for _, u := range unitDurations {
+		if units&u.unit == 0 {
+			continue
 		}
+		v := d / u.duration
+		d -= v * u.duration
+		segments = append(segments, segment{unit: u.unit, value: int(v)})
 	}
+	return segments
+}
 
-	return []string{
-		fmt.Sprintf(firstPartFormat, m),
-		fmt.Sprintf("%02d", s),
+// autoUnits formats time as 00:00:00:00 if it's more than 1 day,
+// 00:00:00 if it's more than 1 hour or 00:00 if it's less than 1 hour
+func autoUnits(d time.Duration) Unit {
+	switch {
+	case d >= 24*time.Hour:
+		return UnitDays | UnitHours | UnitMinutes | UnitSeconds
+	case d >= time.Hour:
+		return UnitHours | UnitMinutes | UnitSeconds
+	default:
+		return UnitMinutes | UnitSeconds
 	}
 }
 
-func findMaxDigitsWidth(d *font.Drawer) (fixed.Int26_6, string) {
+// localizeDigits replaces ASCII digits in s with digits of the numbering system
+// starting with zero rune
+func localizeDigits(s string, zero rune) string {
+	if zero == '0' {
+		return s
+	}
+
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return zero + r - '0'
+		}
+		return r
+	}, s)
+}
+
+func findMaxDigitsWidth(d *font.Drawer, zero rune) (fixed.Int26_6, string) {
 	var (
 		max  fixed.Int26_6
 		maxS string
 	)
 	for i := 0; i < 10; i++ {
-		s := fmt.Sprintf("%d", i)
+		s := string(zero + rune(i))
 		w := d.MeasureString(s)
 		if w > max {
 			max = w
@@ -208,7 +994,20 @@ func choosePalette(frames []image.Image, max int, auto bool) color.Palette {
 		}
 	}
 
-	if !auto && (max == 0 || len(colorsMap) <= max) {
+	// GIF color table can't have more than 256 entries,
+	// if the limit is not set, colors are reduced keeping distinct ones
+	if !auto && max == 0 && len(colorsMap) > 256 {
+		colors := make([]colorCount, 0, len(colorsMap))
+		for c, n := range colorsMap {
+			colors = append(colors, colorCount{c.(color.RGBA), n})
+		}
+		return medianCut(colors, 256)
+	}
+	if max == 0 || max > 256 {
+		max = 256
+	}
+
+	if !auto && len(colorsMap) <= max {
 		// return all colors
 		colors := make([]color.Color, 0, len(colorsMap))
 		for color := range colorsMap {
@@ -230,12 +1029,16 @@ func choosePalette(frames []image.Image, max int, auto bool) color.Palette {
 	}
 
 	sort.Slice(colorsFreq, func(i, j int) bool {
-		return colorsFreq[i].freq > colorsFreq[j].freq
+		if colorsFreq[i].freq != colorsFreq[j].freq {
+			return colorsFreq[i].freq > colorsFreq[j].freq
+		}
+		// break ties by color, so the palette doesn't depend on map order
+		return packRGBA(colorsFreq[i].color.(color.RGBA)) < packRGBA(colorsFreq[j].color.(color.RGBA))
 	})
 
 	if auto {
 		// pick first 10% of most frequent colors
-		max = len(colorsFreq) / 10
+		max = min(len(colorsFreq)/10, 256)
 	}
 
 	colors := make([]color.Color, 0, max)
@@ -2,14 +2,23 @@ package countdown
 
 import (
 	"bytes"
+	"errors"
 	"flag"
+	"image"
+	"image/color"
+	"image/draw"
 	"image/gif"
+	"image/png"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
 	"testing"
 	"time"
 
+	"golang.org/x/image/font/basicfont"
 	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/math/fixed"
 )
 
 // update is a flag to update golden files
@@ -17,6 +26,11 @@ import (
 var update = flag.Bool("update", false, "update golden files")
 
 func TestGenerator_Write(t *testing.T) {
+	logo, err := loadImage("testdata/pattern.png")
+	if err != nil {
+		t.Fatalf("failed to load logo: %v", err)
+	}
+
 	tests := []struct {
 		name    string
 		opts    []Option
@@ -24,108 +38,920 @@ func TestGenerator_Write(t *testing.T) {
 		wantErr bool
 	}{
 		{
-			name: "basic_countdown",
+			name: "basic_countdown",
+			opts: []Option{
+				WithWidth(200),
+				WithHeight(100),
+				WithTimeFrom(10 * time.Second),
+				WithBackgroundColor("black"),
+				WithTextColor("white"),
+				WithMaxFrames(3), // Limit frames for testing
+			},
+			golden: "basic_countdown.gif",
+		},
+		{
+			name: "custom_colors",
+			opts: []Option{
+				WithWidth(200),
+				WithHeight(100),
+				WithTimeFrom(5 * time.Second),
+				WithBackgroundColor("#afa"),
+				WithTextColor("darkviolet"),
+				WithMaxFrames(3),
+			},
+			golden: "custom_colors.gif",
+		},
+		{
+			name: "invalid_target_time",
+			opts: []Option{
+				WithTargetTime(1000), // Past time
+			},
+			wantErr: true,
+		},
+		{
+			name: "with_custom_font",
+			opts: []Option{
+				WithWidth(200),
+				WithHeight(100),
+				WithTimeFrom(5 * time.Second),
+				WithMaxFrames(3),
+				WithFontOpenTypeData(gobold.TTF),
+			},
+			golden: "with_custom_font.gif",
+		},
+		{
+			name: "expired_target_time",
+			opts: []Option{
+				WithWidth(200),
+				WithHeight(100),
+				WithTargetTime(1000),
+				WithExpiredHold(),
+			},
+			golden: "expired_target_time.gif",
+		},
+		{
+			name: "expired_text",
+			opts: []Option{
+				WithWidth(200),
+				WithHeight(100),
+				WithTimeFrom(2 * time.Second),
+				WithFontSize(28),
+				WithFontOpenTypeData(gobold.TTF),
+				WithExpiredText("Sale ended"),
+			},
+			golden: "expired_text.gif",
+		},
+		{
+			name: "expired_fractional_time_from",
+			opts: []Option{
+				WithWidth(200),
+				WithHeight(100),
+				WithTimeFrom(2*time.Second + 500*time.Millisecond),
+				WithFontSize(28),
+				WithFontOpenTypeData(gobold.TTF),
+				WithExpiredText("Sale ended"),
+			},
+			golden: "expired_fractional_time_from.gif",
+		},
+		{
+			name: "expired_background_image",
+			opts: []Option{
+				WithWidth(200),
+				WithHeight(100),
+				WithTimeFrom(1 * time.Second),
+				WithFontOpenTypeData(gobold.TTF),
+				WithTextColor("black"),
+				WithExpiredBackgroundImagePath("testdata/bg.png"),
+			},
+			golden: "expired_background_image.gif",
+		},
+		{
+			name: "with_invalid_font",
+			opts: []Option{
+				WithWidth(200),
+				WithHeight(100),
+				WithTimeFrom(5 * time.Second),
+				WithMaxFrames(3),
+				WithFontOpenTypeData([]byte{0x00, 0x01, 0x02}),
+			},
+			wantErr: true,
+		},
+		{
+			name: "without_leading_zeros",
+			opts: []Option{
+				WithWidth(200),
+				WithHeight(100),
+				WithTimeFrom(5 * time.Second),
+				WithMaxFrames(3),
+				WithFontOpenTypeData(gobold.TTF),
+				WithoutLeadingZeros(),
+			},
+			golden: "without_leading_zeros.gif",
+		},
+		{
+			name: "with_colon_compensation_auto",
+			opts: []Option{
+				WithWidth(200),
+				WithHeight(100),
+				WithTimeFrom(5 * time.Second),
+				WithMaxFrames(3),
+				WithFontOpenTypeData(gobold.TTF),
+				WithColonCompensationAuto(),
+			},
+			golden: "with_colon_compensation_auto.gif",
+		},
+		{
+			name: "with_background_image",
+			opts: []Option{
+				WithWidth(200),
+				WithHeight(100),
+				WithTimeFrom(5 * time.Second),
+				WithMaxFrames(3),
+				WithFontOpenTypeData(gobold.TTF),
+				WithTextColor("black"),
+				WithBackgroundImagePath("testdata/bg.png"),
+			},
+			golden: "with_background_image.gif",
+		},
+		{
+			name: "with_palette_max_colors",
+			opts: []Option{
+				WithWidth(200),
+				WithHeight(100),
+				WithTimeFrom(5 * time.Second),
+				WithMaxFrames(3),
+				WithFontOpenTypeData(gobold.TTF),
+				WithTextColor("black"),
+				WithBackgroundImagePath("testdata/bg.png"),
+				WithPaletteMaxColors(64),
+			},
+			golden: "with_palette_max_colors.gif",
+		},
+		{
+			name: "with_days",
+			opts: []Option{
+				WithWidth(320),
+				WithHeight(100),
+				WithTimeFrom(9*24*time.Hour + 5*time.Second),
+				WithMaxFrames(3),
+				WithFontOpenTypeData(gobold.TTF),
+			},
+			golden: "with_days.gif",
+		},
+		{
+			name: "with_units",
+			opts: []Option{
+				WithWidth(200),
+				WithHeight(100),
+				WithTimeFrom(2*time.Hour + 5*time.Second),
+				WithMaxFrames(3),
+				WithFontOpenTypeData(gobold.TTF),
+				WithUnits("ms"),
+			},
+			golden: "with_units.gif",
+		},
+		{
+			name: "with_labels",
+			opts: []Option{
+				WithWidth(200),
+				WithHeight(100),
+				WithTimeFrom(2*time.Hour + 5*time.Second),
+				WithMaxFrames(3),
+				WithFontSize(36),
+				WithFontOpenTypeData(gobold.TTF),
+				WithLabels("DAYS,HOURS,MINUTES,SECONDS"),
+				WithLabelFontSize(10),
+				WithLabelFontOpenTypeData(gobold.TTF),
+				WithLabelColor("gray"),
+			},
+			golden: "with_labels.gif",
+		},
+		{
+			name: "with_locale",
+			opts: []Option{
+				WithWidth(200),
+				WithHeight(100),
+				WithTimeFrom(21*time.Minute + 2*time.Second),
+				WithMaxFrames(3),
+				WithFontSize(36),
+				WithFontOpenTypeData(gobold.TTF),
+				WithLocale("ru-RU"),
+				WithLabelFontSize(12),
+				WithLabelFontOpenTypeData(gobold.TTF),
+			},
+			golden: "with_locale.gif",
+		},
+		{
+			name: "with_invalid_locale",
+			opts: []Option{
+				WithLocale("xx"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "with_rtl",
+			opts: []Option{
+				WithWidth(200),
+				WithHeight(100),
+				WithTimeFrom(2*time.Hour + 5*time.Second),
+				WithMaxFrames(3),
+				WithFontSize(36),
+				WithFontOpenTypeData(gobold.TTF),
+				WithoutLeadingZeros(),
+				WithLabels("D,H,M,S"),
+				WithLabelFontSize(12),
+				WithLabelFontOpenTypeData(gobold.TTF),
+				WithRTL(),
+			},
+			golden: "with_rtl.gif",
+		},
+		{
+			name: "with_rtl_label_align",
+			opts: []Option{
+				WithWidth(200),
+				WithHeight(100),
+				WithTimeFrom(2*time.Hour + 5*time.Second),
+				WithMaxFrames(3),
+				WithFontSize(36),
+				WithFontOpenTypeData(gobold.TTF),
+				WithLabels("D,H,M,S"),
+				WithLabelFontSize(12),
+				WithLabelFontOpenTypeData(gobold.TTF),
+				WithLabelAlign("start"),
+				WithRTL(),
+			},
+			golden: "with_rtl_label_align.gif",
+		},
+		{
+			name: "with_label_align",
+			opts: []Option{
+				WithWidth(200),
+				WithHeight(100),
+				WithTimeFrom(2*time.Hour + 5*time.Second),
+				WithMaxFrames(3),
+				WithFontSize(36),
+				WithFontOpenTypeData(gobold.TTF),
+				WithLabels("D,H,M,S"),
+				WithLabelFontSize(12),
+				WithLabelFontOpenTypeData(gobold.TTF),
+				WithLabelAlign("start"),
+			},
+			golden: "with_label_align.gif",
+		},
+		{
+			name: "with_invalid_label_align",
+			opts: []Option{
+				WithLabelAlign("left"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "with_format",
+			opts: []Option{
+				WithWidth(200),
+				WithHeight(100),
+				WithTimeFrom(2*time.Hour + 5*time.Second),
+				WithMaxFrames(3),
+				WithFontSize(28),
+				WithFontOpenTypeData(gobold.TTF),
+				WithFormat("{h}h {mm}m {ss}s"),
+			},
+			golden: "with_format.gif",
+		},
+		{
+			name: "with_format_and_labels",
+			opts: []Option{
+				WithWidth(200),
+				WithHeight(100),
+				WithTimeFrom(2*time.Hour + 5*time.Second),
+				WithMaxFrames(3),
+				WithFontSize(28),
+				WithFontOpenTypeData(gobold.TTF),
+				WithFormat("T-{m}:{ss}"),
+				WithColonCompensationAuto(),
+				WithLabels("D,H,MIN,SEC"),
+				WithLabelFontSize(10),
+				WithLabelFontOpenTypeData(gobold.TTF),
+			},
+			golden: "with_format_and_labels.gif",
+		},
+		{
+			name: "count_up",
+			opts: []Option{
+				WithWidth(200),
+				WithHeight(100),
+				WithTimeFrom(59*time.Minute + 58*time.Second),
+				WithMaxFrames(3),
+				WithFontSize(36),
+				WithFontOpenTypeData(gobold.TTF),
+				WithCountUp(),
+			},
+			golden: "count_up.gif",
+		},
+		{
+			name: "count_up_default_max_frames",
+			opts: []Option{
+				WithWidth(100),
+				WithHeight(50),
+				WithCountUp(),
+			},
+			golden: "count_up_default_max_frames.gif",
+		},
+		{
+			name: "invalid_start_time",
+			opts: []Option{
+				WithStartTime(int(time.Now().Add(time.Hour).Unix())),
+			},
+			wantErr: true,
+		},
+		{
+			name: "count_up_negative_time_from",
+			opts: []Option{
+				WithTimeFrom(-3 * time.Second),
+				WithCountUp(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "with_fps",
+			opts: []Option{
+				WithWidth(200),
+				WithHeight(100),
+				WithTimeFrom(1 * time.Second),
+				WithFontSize(28),
+				WithFontOpenTypeData(gobold.TTF),
+				WithFPS(3),
+				WithFractionDigits(2),
+			},
+			golden: "with_fps.gif",
+		},
+		{
+			name: "with_fps_format",
+			opts: []Option{
+				WithWidth(200),
+				WithHeight(100),
+				WithTimeFrom(2 * time.Minute),
+				WithMaxFrames(4),
+				WithFontSize(28),
+				WithFontOpenTypeData(gobold.TTF),
+				WithFPS(4),
+				WithFormat("{m}:{ss}.{f}"),
+			},
+			golden: "with_fps_format.gif",
+		},
+		{
+			name: "with_invalid_fps",
+			opts: []Option{
+				WithFPS(100),
+			},
+			wantErr: true,
+		},
+		{
+			name: "with_flip_clock",
+			opts: []Option{
+				WithWidth(200),
+				WithHeight(100),
+				WithTimeFrom(1*time.Minute + 1*time.Second),
+				WithMaxFrames(3),
+				WithFontSize(32),
+				WithFontOpenTypeData(gobold.TTF),
+				WithFlipClock(),
+				WithCardPadding(4),
+				WithCardColor("#444"),
+			},
+			golden: "with_flip_clock.gif",
+		},
+		{
+			name: "with_slide_transition",
+			opts: []Option{
+				WithWidth(200),
+				WithHeight(100),
+				WithTimeFrom(1*time.Minute + 1*time.Second),
+				WithMaxFrames(2),
+				WithFontSize(32),
+				WithFontOpenTypeData(gobold.TTF),
+				WithTransition("slide"),
+				WithTransitionDuration(0.5),
+			},
+			golden: "with_slide_transition.gif",
+		},
+		{
+			name: "with_fade_transition",
+			opts: []Option{
+				WithWidth(200),
+				WithHeight(100),
+				WithTimeFrom(1*time.Minute + 1*time.Second),
+				WithMaxFrames(2),
+				WithFontSize(32),
+				WithFontOpenTypeData(gobold.TTF),
+				WithTransition("fade"),
+				WithTransitionDuration(0.5),
+			},
+			golden: "with_fade_transition.gif",
+		},
+		{
+			name: "with_roll_transition",
+			opts: []Option{
+				WithWidth(200),
+				WithHeight(100),
+				WithTimeFrom(1*time.Minute + 1*time.Second),
+				WithMaxFrames(2),
+				WithFontSize(32),
+				WithFontOpenTypeData(gobold.TTF),
+				WithTransition("roll"),
+				WithTransitionDuration(0.5),
+			},
+			golden: "with_roll_transition.gif",
+		},
+		{
+			name: "with_blinking_separator",
+			opts: []Option{
+				WithWidth(200),
+				WithHeight(100),
+				WithTimeFrom(1*time.Minute + 1*time.Second),
+				WithMaxFrames(2),
+				WithFontSize(32),
+				WithFontOpenTypeData(gobold.TTF),
+				WithBlinkingSeparator(),
+			},
+			golden: "with_blinking_separator.gif",
+		},
+		{
+			name: "with_separator_dim_color",
+			opts: []Option{
+				WithWidth(200),
+				WithHeight(100),
+				WithTimeFrom(1*time.Minute + 1*time.Second),
+				WithMaxFrames(4),
+				WithFPS(3),
+				WithFontSize(32),
+				WithFontOpenTypeData(gobold.TTF),
+				WithBlinkingSeparator(),
+				WithSeparatorDimColor("#555"),
+			},
+			golden: "with_separator_dim_color.gif",
+		},
+		{
+			name: "with_separators",
+			opts: []Option{
+				WithWidth(320),
+				WithHeight(100),
+				WithTimeFrom(26*time.Hour + 3*time.Minute + 4*time.Second),
+				WithMaxFrames(2),
+				WithUnits("dhms"),
+				WithFontSize(32),
+				WithFontOpenTypeData(gobold.TTF),
+				WithSeparators("d ,h ,m "),
+			},
+			golden: "with_separators.gif",
+		},
+		{
+			name: "with_separators_colon_compensation",
+			opts: []Option{
+				WithWidth(320),
+				WithHeight(100),
+				WithTimeFrom(26*time.Hour + 3*time.Minute + 4*time.Second),
+				WithMaxFrames(2),
+				WithUnits("dhms"),
+				WithFontSize(32),
+				WithFontOpenTypeData(gobold.TTF),
+				WithSeparators("d ,,"),
+				WithColonCompensationAuto(),
+			},
+			golden: "with_separators_colon_compensation.gif",
+		},
+		{
+			name: "with_separator_image",
+			opts: []Option{
+				WithWidth(200),
+				WithHeight(100),
+				WithTimeFrom(1*time.Minute + 1*time.Second),
+				WithMaxFrames(2),
+				WithFontSize(32),
+				WithFontOpenTypeData(gobold.TTF),
+				WithSeparatorImagePath("testdata/separator.png"),
+				WithSeparatorOffsets("2"),
+			},
+			golden: "with_separator_image.gif",
+		},
+		{
+			name: "with_invalid_separators",
+			opts: []Option{
+				WithSeparators("d,h"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "with_invalid_separator_offsets",
+			opts: []Option{
+				WithSeparatorOffsets("up"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "with_color_rules",
+			opts: []Option{
+				WithWidth(200),
+				WithHeight(100),
+				WithTimeFrom(5 * time.Minute),
+				WithMaxFrames(2),
+				WithFontSize(32),
+				WithFontOpenTypeData(gobold.TTF),
+				WithColorRules("s:orange,5m:red"),
+			},
+			golden: "with_color_rules.gif",
+		},
+		{
+			name: "with_invalid_color_rules",
+			opts: []Option{
+				WithColorRules("x:red"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "with_stroke_and_shadow",
+			opts: []Option{
+				WithWidth(200),
+				WithHeight(100),
+				WithTimeFrom(1*time.Minute + 1*time.Second),
+				WithMaxFrames(2),
+				WithFontSize(32),
+				WithFontOpenTypeData(gobold.TTF),
+				WithBackgroundImagePath("testdata/bg.png"),
+				WithStrokeWidth(2),
+				WithStrokeColor("#222"),
+				WithShadowColor("black"),
+				WithShadowOffset(3, 3),
+				WithShadowBlur(4),
+			},
+			golden: "with_stroke_and_shadow.gif",
+		},
+		{
+			name: "with_invalid_stroke_width",
+			opts: []Option{
+				WithStrokeWidth(-1),
+			},
+			wantErr: true,
+		},
+		{
+			name: "with_gradients",
+			opts: []Option{
+				WithWidth(200),
+				WithHeight(100),
+				WithTimeFrom(1*time.Minute + 1*time.Second),
+				WithMaxFrames(2),
+				WithFontSize(32),
+				WithFontOpenTypeData(gobold.TTF),
+				WithBackgroundGradient("radial-gradient(#335, #001)"),
+				WithTextGradient("linear-gradient(#fff, gold 40%, orange)"),
+			},
+			golden: "with_gradients.gif",
+		},
+		{
+			name: "with_invalid_gradient",
+			opts: []Option{
+				WithTextGradient("conic-gradient(red, blue)"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "with_background_fit_cover",
+			opts: []Option{
+				WithWidth(200),
+				WithHeight(100),
+				WithTimeFrom(10 * time.Second),
+				WithMaxFrames(1),
+				WithBackgroundImagePath("testdata/pattern.png"),
+				WithBackgroundFit("cover"),
+			},
+			golden: "with_background_fit_cover.gif",
+		},
+		{
+			name: "with_background_fit_contain",
+			opts: []Option{
+				WithWidth(200),
+				WithHeight(100),
+				WithTimeFrom(10 * time.Second),
+				WithMaxFrames(1),
+				WithBackgroundImagePath("testdata/pattern.png"),
+				WithBackgroundFit("contain"),
+				WithBackgroundAnchor("left"),
+				WithBackgroundOffset(10, 0),
+			},
+			golden: "with_background_fit_contain.gif",
+		},
+		{
+			name: "with_background_fit_tile",
+			opts: []Option{
+				WithWidth(200),
+				WithHeight(100),
+				WithTimeFrom(10 * time.Second),
+				WithMaxFrames(1),
+				WithBackgroundImagePath("testdata/pattern.png"),
+				WithBackgroundFit("tile"),
+				WithBackgroundAnchor("top-left"),
+				WithBackgroundOffset(-5, -5),
+			},
+			golden: "with_background_fit_tile.gif",
+		},
+		{
+			name: "with_invalid_background_fit",
+			opts: []Option{
+				WithBackgroundFit("fill"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "with_invalid_background_anchor",
+			opts: []Option{
+				WithBackgroundAnchor("middle"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "with_animated_background",
+			opts: []Option{
+				WithWidth(200),
+				WithHeight(100),
+				WithTimeFrom(10 * time.Second),
+				WithMaxFrames(2),
+				WithBackgroundImagePath("testdata/snow.gif"),
+				WithBackgroundFit("tile"),
+			},
+			golden: "with_animated_background.gif",
+		},
+		{
+			name: "with_animated_background_blinking",
+			opts: []Option{
+				WithWidth(200),
+				WithHeight(100),
+				WithTimeFrom(10 * time.Second),
+				WithMaxFrames(2),
+				WithBackgroundImagePath("testdata/snow.gif"),
+				WithBackgroundFit("tile"),
+				WithBlinkingSeparator(),
+			},
+			golden: "with_animated_background_blinking.gif",
+		},
+		{
+			name: "with_timer_anchor",
+			opts: []Option{
+				WithWidth(300),
+				WithHeight(150),
+				WithTimeFrom(1*time.Minute + 1*time.Second),
+				WithMaxFrames(2),
+				WithFontSize(32),
+				WithFontOpenTypeData(gobold.TTF),
+				WithLabels(",,MIN,SEC"),
+				WithBackgroundImagePath("testdata/bg.png"),
+				WithTimerAnchor("bottom-right"),
+				WithTimerPadding(10),
+			},
+			golden: "with_timer_anchor.gif",
+		},
+		{
+			name: "with_timer_position",
+			opts: []Option{
+				WithWidth(300),
+				WithHeight(150),
+				WithTimeFrom(1*time.Minute + 1*time.Second),
+				WithMaxFrames(2),
+				WithFontSize(32),
+				WithFontOpenTypeData(gobold.TTF),
+				WithTimerAnchor("top-left"),
+				WithTimerPosition(20, 30),
+			},
+			golden: "with_timer_position.gif",
+		},
+		{
+			name: "with_font_auto_fit",
+			opts: []Option{
+				WithWidth(300),
+				WithHeight(150),
+				WithTimeFrom(1*time.Hour + 1*time.Second),
+				WithMaxFrames(2),
+				WithFontOpenTypeData(gobold.TTF),
+				WithLabels(",HOURS,MIN,SEC"),
+				WithLabelFontSize(12),
+				WithLabelFontOpenTypeData(gobold.TTF),
+				WithFontAutoFit(20),
+			},
+			golden: "with_font_auto_fit.gif",
+		},
+		{
+			name: "with_font_auto_fit_without_font",
+			opts: []Option{
+				WithFontAutoFit(0),
+			},
+			wantErr: true,
+		},
+		{
+			name: "with_invalid_font_auto_fit_margin",
+			opts: []Option{
+				WithFontOpenTypeData(gobold.TTF),
+				WithFontAutoFit(-1),
+			},
+			wantErr: true,
+		},
+		{
+			name: "with_title_and_subtitle",
+			opts: []Option{
+				WithWidth(300),
+				WithHeight(200),
+				WithTimeFrom(1*time.Minute + 1*time.Second),
+				WithMaxFrames(2),
+				WithFontSize(36),
+				WithFontOpenTypeData(gobold.TTF),
+				WithTitleFontSize(18),
+				WithTitleFontOpenTypeData(gobold.TTF),
+				WithTitle("Black Friday ends in"),
+				WithTitleColor("gold"),
+				WithSubtitle("Free shipping on all orders over $50, no code needed"),
+				WithSubtitleWidth(200),
+			},
+			golden: "with_title_and_subtitle.gif",
+		},
+		{
+			name: "with_title_top_left",
+			opts: []Option{
+				WithWidth(300),
+				WithHeight(150),
+				WithTimeFrom(1 * time.Second),
+				WithFontSize(32),
+				WithFontOpenTypeData(gobold.TTF),
+				WithTitle("Sale ends in"),
+				WithTimerAnchor("top-left"),
+				WithTimerPadding(10),
+				WithExpiredText("Sale ended"),
+			},
+			golden: "with_title_top_left.gif",
+		},
+		{
+			name: "with_invalid_title_width",
+			opts: []Option{
+				WithTitleWidth(-1),
+			},
+			wantErr: true,
+		},
+		{
+			name: "with_layers",
+			opts: []Option{
+				WithWidth(300),
+				WithHeight(150),
+				WithTimeFrom(3 * time.Second),
+				WithMaxFrames(2),
+				WithFontSize(32),
+				WithFontOpenTypeData(gobold.TTF),
+				WithLayers(
+					ImageLayer{Image: *logo, Anchor: AnchorTopRight, Offset: image.Pt(-10, 10)},
+					CountdownLayer,
+					LayerFunc(func(dst draw.Image, state FrameState) {
+						// bar shrinking with the timer value
+						r := image.Rect(0, 146, int(state.Value/time.Second)*100, 150)
+						draw.Draw(dst, r, image.NewUniform(color.RGBA{0xff, 0, 0, 0xff}), image.Point{}, draw.Src)
+					}),
+				),
+				WithLayers(TextLayer{Text: "LIMITED OFFER", Anchor: AnchorBottomLeft, Offset: image.Pt(10, -14)}),
+			},
+			golden: "with_layers.gif",
+		},
+		{
+			name: "with_progress_bar",
+			opts: []Option{
+				WithWidth(300),
+				WithHeight(150),
+				WithTimeFrom(4 * time.Second),
+				WithMaxFrames(3),
+				WithFontSize(32),
+				WithFontOpenTypeData(gobold.TTF),
+				WithProgressBar(""),
+				WithProgressColor("orange"),
+				WithProgressTrackColor("#333"),
+				WithProgressSize(200),
+				WithProgressOffset(0, -20),
+			},
+			golden: "with_progress_bar.gif",
+		},
+		{
+			name: "with_progress_ring",
 			opts: []Option{
-				WithWidth(200),
-				WithHeight(100),
-				WithTimeFrom(10 * time.Second),
-				WithBackgroundColor("black"),
-				WithTextColor("white"),
-				WithMaxFrames(3), // Limit frames for testing
+				WithWidth(300),
+				WithHeight(150),
+				WithTimeFrom(3 * time.Second),
+				WithFontSize(24),
+				WithFontOpenTypeData(gobold.TTF),
+				WithProgressRing(""),
+				WithProgressTrackColor("#333"),
+				WithProgressThickness(10),
+				WithProgressSize(130),
 			},
-			golden: "basic_countdown.gif",
+			golden: "with_progress_ring.gif",
 		},
 		{
-			name: "custom_colors",
+			name: "with_invalid_progress_anchor",
 			opts: []Option{
-				WithWidth(200),
-				WithHeight(100),
-				WithTimeFrom(5 * time.Second),
-				WithBackgroundColor("#afa"),
-				WithTextColor("darkviolet"),
-				WithMaxFrames(3),
+				WithProgressBar("middle"),
 			},
-			golden: "custom_colors.gif",
+			wantErr: true,
 		},
 		{
-			name: "invalid_target_time",
+			name: "with_invalid_progress_thickness",
 			opts: []Option{
-				WithTargetTime(1000), // Past time
+				WithProgressThickness(0),
 			},
 			wantErr: true,
 		},
 		{
-			name: "with_custom_font",
+			name: "with_digit_sprites",
 			opts: []Option{
-				WithWidth(200),
+				WithWidth(300),
 				WithHeight(100),
-				WithTimeFrom(5 * time.Second),
-				WithMaxFrames(3),
+				WithTimeFrom(1*time.Minute + 1*time.Second),
+				WithMaxFrames(2),
+				WithFontSize(32),
 				WithFontOpenTypeData(gobold.TTF),
+				WithFormat("T-{m}:{ss}"),
+				WithDigitSpritesPath("testdata/digits.png"),
 			},
-			golden: "with_custom_font.gif",
+			golden: "with_digit_sprites.gif",
 		},
 		{
-			name: "with_invalid_font",
+			name: "with_invalid_digit_sprites",
 			opts: []Option{
-				WithWidth(200),
-				WithHeight(100),
-				WithTimeFrom(5 * time.Second),
-				WithMaxFrames(3),
-				WithFontOpenTypeData([]byte{0x00, 0x01, 0x02}),
+				WithDigitSpritesPath("testdata/bg.png"), // 200 pixels wide
 			},
 			wantErr: true,
 		},
 		{
-			name: "without_leading_zeros",
+			name: "with_fallback_font",
 			opts: []Option{
-				WithWidth(200),
-				WithHeight(100),
-				WithTimeFrom(5 * time.Second),
-				WithMaxFrames(3),
+				WithWidth(300),
+				WithHeight(150),
+				WithTimeFrom(1*time.Minute + 1*time.Second),
+				WithMaxFrames(2),
+				WithFontSize(32),
 				WithFontOpenTypeData(gobold.TTF),
-				WithoutLeadingZeros(),
+				WithLabels(",,МИН,СЕК"),
+				WithTitle("Sale ends: распродажа"),
+				WithFallbackFontOpenTypeData(gobold.TTF),
 			},
-			golden: "without_leading_zeros.gif",
+			golden: "with_fallback_font.gif",
 		},
 		{
-			name: "with_colon_compensation_auto",
+			name: "with_missing_glyphs",
 			opts: []Option{
-				WithWidth(200),
-				WithHeight(100),
-				WithTimeFrom(5 * time.Second),
-				WithMaxFrames(3),
-				WithFontOpenTypeData(gobold.TTF),
-				WithColonCompensationAuto(),
+				WithLabels(",,МИН,СЕК"),
 			},
-			golden: "with_colon_compensation_auto.gif",
+			wantErr: true,
 		},
 		{
-			name: "with_background_image",
+			name: "with_invalid_timer_anchor",
 			opts: []Option{
-				WithWidth(200),
-				WithHeight(100),
-				WithTimeFrom(5 * time.Second),
-				WithMaxFrames(3),
-				WithFontOpenTypeData(gobold.TTF),
-				WithTextColor("black"),
-				WithBackgroundImagePath("testdata/bg.png"),
+				WithTimerAnchor("somewhere"),
 			},
-			golden: "with_background_image.gif",
+			wantErr: true,
 		},
 		{
-			name: "with_palette_max_colors",
+			name: "with_invalid_transition",
 			opts: []Option{
-				WithWidth(200),
-				WithHeight(100),
-				WithTimeFrom(5 * time.Second),
-				WithMaxFrames(3),
-				WithFontOpenTypeData(gobold.TTF),
-				WithTextColor("black"),
-				WithBackgroundImagePath("testdata/bg.png"),
-				WithPaletteMaxColors(64),
+				WithTransition("spin"),
 			},
-			golden: "with_palette_max_colors.gif",
+			wantErr: true,
+		},
+		{
+			name: "with_invalid_transition_duration",
+			opts: []Option{
+				WithTransitionDuration(1.5),
+			},
+			wantErr: true,
+		},
+		{
+			name: "with_invalid_format",
+			opts: []Option{
+				WithFormat("{x}:{mm}"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "with_invalid_numbering_system",
+			opts: []Option{
+				WithNumberingSystem("roman"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "with_invalid_labels",
+			opts: []Option{
+				WithLabels("HOURS,MINUTES"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "with_invalid_units",
+			opts: []Option{
+				WithUnits("dhx"),
+			},
+			wantErr: true,
 		},
 	}
 
@@ -244,7 +1070,9 @@ func TestFormatTime(t *testing.T) {
 	tests := []struct {
 		name           string
 		duration       time.Duration
+		units          Unit
 		noLeadingZeros bool
+		fractionDigits int
 		want           []string
 	}{
 		{
@@ -263,11 +1091,51 @@ func TestFormatTime(t *testing.T) {
 			noLeadingZeros: true,
 			want:           []string{"2", "05", "45"},
 		},
+		{
+			name:     "days",
+			duration: 9*24*time.Hour + 2*time.Hour + 30*time.Minute + 45*time.Second,
+			want:     []string{"09", "02", "30", "45"},
+		},
+		{
+			name:     "days_and_hours",
+			duration: 9*24*time.Hour + 2*time.Hour + 30*time.Minute + 45*time.Second,
+			units:    UnitDays | UnitHours,
+			want:     []string{"09", "02"},
+		},
+		{
+			name:     "minutes_overflow",
+			duration: 2*time.Hour + 5*time.Minute + 45*time.Second,
+			units:    UnitMinutes | UnitSeconds,
+			want:     []string{"125", "45"},
+		},
+		{
+			name:     "hours_overflow",
+			duration: 9*24*time.Hour + 2*time.Hour + 30*time.Minute,
+			units:    UnitHours | UnitMinutes,
+			want:     []string{"218", "30"},
+		},
+		{
+			name:           "fraction_last_minute",
+			duration:       59*time.Second + 876*time.Millisecond,
+			fractionDigits: 2,
+			want:           []string{"00", "59", "87"},
+		},
+		{
+			name:           "fraction_before_last_minute",
+			duration:       time.Minute + 500*time.Millisecond,
+			fractionDigits: 1,
+			want:           []string{"01", "00"},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := formatTime(tt.duration, tt.noLeadingZeros)
+			var got []string
+			for _, s := range formatTime(tt.duration, tt.units, tt.noLeadingZeros, tt.fractionDigits) {
+				if s.unit != 0 {
+					got = append(got, s.text)
+				}
+			}
 			if !compareStringSlices(got, tt.want) {
 				t.Errorf("formatTime() = %v, want %v", got, tt.want)
 			}
@@ -275,6 +1143,117 @@ func TestFormatTime(t *testing.T) {
 	}
 }
 
+func TestFrameDelay(t *testing.T) {
+	for _, fps := range []int{1, 2, 3, 4, 7, 10, 30, 50} {
+		var total int
+		for i := 0; i < fps; i++ {
+			delay := frameDelay(i, fps)
+			if delay < 100/fps || delay > 100/fps+1 {
+				t.Errorf("fps %d: frame %d delay %d", fps, i, delay)
+			}
+			total += delay
+		}
+		if total != 100 {
+			t.Errorf("fps %d: total delay %d, want 100", fps, total)
+		}
+	}
+}
+
+func TestTransitionDelays(t *testing.T) {
+	tests := []struct {
+		delay    int
+		frames   int
+		duration float64
+		want     []int
+	}{
+		{100, 4, 0.3, []int{7, 8, 7, 8}},
+		{100, 3, 0.5, []int{16, 17, 17}},
+		{25, 4, 0.3, []int{2, 2, 2, 2}},
+		{10, 4, 0.5, []int{2, 3}},
+		{10, 4, 0.1, nil},
+		{100, 4, 1, []int{24, 25, 24, 25}},
+		{33, 4, 1, []int{7, 8, 8, 8}},
+		{3, 4, 1, nil},
+	}
+
+	for _, tt := range tests {
+		got := transitionDelays(tt.delay, tt.frames, tt.duration)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("transitionDelays(%d, %d, %v) = %v, want %v", tt.delay, tt.frames, tt.duration, got, tt.want)
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		want    []token
+		wantErr bool
+	}{
+		{
+			name:   "units",
+			format: "{d}d {hh}h {mm}m",
+			want: []token{
+				{unit: UnitDays, width: 1},
+				{text: "d "},
+				{unit: UnitHours, width: 2},
+				{text: "h "},
+				{unit: UnitMinutes, width: 2},
+				{text: "m"},
+			},
+		},
+		{
+			name:   "prefix",
+			format: "T-{h}:{mm}:{ss}",
+			want: []token{
+				{text: "T-"},
+				{unit: UnitHours, width: 1},
+				{text: ":"},
+				{unit: UnitMinutes, width: 2},
+				{text: ":"},
+				{unit: UnitSeconds, width: 2},
+			},
+		},
+		{
+			name:   "escaped_braces",
+			format: "{{{s}}}",
+			want: []token{
+				{text: "{"},
+				{unit: UnitSeconds, width: 1},
+				{text: "}"},
+			},
+		},
+		{
+			name:   "fraction",
+			format: "{s}.{ff}",
+			want: []token{
+				{unit: UnitSeconds, width: 1},
+				{text: "."},
+				{unit: UnitFraction, width: 2},
+			},
+		},
+		{"no_fields", "sale", nil, true},
+		{"unknown_field", "{x}", nil, true},
+		{"mixed_field", "{hm}", nil, true},
+		{"empty_field", "{}", nil, true},
+		{"unclosed_field", "{mm", nil, true},
+		{"unexpected_brace", "{mm}}", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseFormat(tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFormat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("parseFormat() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func compareStringSlices(a, b []string) bool {
 	if len(a) != len(b) {
 		return false
@@ -286,3 +1265,267 @@ func compareStringSlices(a, b []string) bool {
 	}
 	return true
 }
+
+func TestParseColorRules(t *testing.T) {
+	red := color.RGBA{0xff, 0x00, 0x00, 0xff}
+	orange := color.RGBA{0xff, 0xa5, 0x00, 0xff}
+
+	tests := []struct {
+		rules   string
+		want    []ColorRule
+		wantErr bool
+	}{
+		{
+			rules: "s:orange",
+			want:  []ColorRule{{Units: UnitSeconds, Color: orange}},
+		},
+		{
+			rules: "hm:red,5m:orange",
+			want: []ColorRule{
+				{Units: UnitHours | UnitMinutes, Color: red},
+				{Below: 5 * time.Minute, Color: orange},
+			},
+		},
+		{
+			rules: "sf@10s:#f00",
+			want:  []ColorRule{{Units: UnitSeconds | UnitFraction, Below: 10 * time.Second, Color: red}},
+		},
+		{rules: "s", wantErr: true},
+		{rules: "s:", wantErr: true},
+		{rules: ":red", wantErr: true},
+		{rules: "x:red", wantErr: true},
+		{rules: "s@soon:red", wantErr: true},
+		{rules: "s:nocolor", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseColorRules(tt.rules)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseColorRules(%q) error = %v, wantErr %v", tt.rules, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseColorRules(%q) = %v, want %v", tt.rules, got, tt.want)
+		}
+	}
+}
+
+func TestParseGradient(t *testing.T) {
+	red := color.RGBA{0xff, 0x00, 0x00, 0xff}
+	blue := color.RGBA{0x00, 0x00, 0xff, 0xff}
+	white := color.RGBA{0xff, 0xff, 0xff, 0xff}
+
+	tests := []struct {
+		gradient string
+		want     *Gradient
+		wantErr  bool
+	}{
+		{
+			gradient: "linear-gradient(red, blue)",
+			want: &Gradient{
+				Angle: 180,
+				Stops: []GradientStop{{0, red}, {1, blue}},
+			},
+		},
+		{
+			gradient: "linear-gradient(to right, red, white, blue 80%)",
+			want: &Gradient{
+				Angle: 90,
+				Stops: []GradientStop{{0, red}, {0.4, white}, {0.8, blue}},
+			},
+		},
+		{
+			gradient: "linear-gradient(45deg, red 20%, blue)",
+			want: &Gradient{
+				Angle: 45,
+				Stops: []GradientStop{{0.2, red}, {1, blue}},
+			},
+		},
+		{
+			gradient: "radial-gradient(circle, white, red, blue)",
+			want: &Gradient{
+				Radial: true,
+				Circle: true,
+				Angle:  180,
+				Stops:  []GradientStop{{0, white}, {0.5, red}, {1, blue}},
+			},
+		},
+		{gradient: "red", wantErr: true},
+		{gradient: "linear-gradient(red)", wantErr: true},
+		{gradient: "linear-gradient(to middle, red, blue)", wantErr: true},
+		{gradient: "linear-gradient(red 10, blue)", wantErr: true},
+		{gradient: "linear-gradient(red, , blue)", wantErr: true},
+		{gradient: "radial-gradient(45deg, red, blue)", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseGradient(tt.gradient)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseGradient(%q) error = %v, wantErr %v", tt.gradient, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseGradient(%q) = %+v, want %+v", tt.gradient, got, tt.want)
+		}
+	}
+}
+
+func TestGradientAt(t *testing.T) {
+	black := color.RGBA{0x00, 0x00, 0x00, 0xff}
+	white := color.RGBA{0xff, 0xff, 0xff, 0xff}
+
+	gr := (&Gradient{
+		Angle: 90,
+		Stops: []GradientStop{{0, black}, {1, white}},
+	}).In(image.Rect(0, 0, 100, 10))
+
+	tests := []struct {
+		x    int
+		want uint32
+	}{
+		{-10, 0},
+		{0, 0x0148},
+		{49, 0x7eb8},
+		{99, 0xfeb7},
+		{200, 0xffff},
+	}
+
+	for _, tt := range tests {
+		r, _, _, _ := gr.At(tt.x, 5).RGBA()
+		if r != tt.want {
+			t.Errorf("At(%d, 5) red = %#x, want %#x", tt.x, r, tt.want)
+		}
+	}
+}
+
+func TestAnimationFrameAt(t *testing.T) {
+	a := &Animation{Delays: []int{30, 30, 40}}
+
+	tests := []struct {
+		t         int
+		wantIndex int
+		wantLeft  int
+	}{
+		{0, 0, 30},
+		{29, 0, 1},
+		{30, 1, 30},
+		{75, 2, 25},
+		{100, 0, 30},
+		{250, 1, 10},
+	}
+
+	for _, tt := range tests {
+		index, left := a.frameAt(tt.t)
+		if index != tt.wantIndex || left != tt.wantLeft {
+			t.Errorf("frameAt(%d) = %d, %d, want %d, %d", tt.t, index, left, tt.wantIndex, tt.wantLeft)
+		}
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	// basicfont glyphs are 7 pixels wide
+	face := basicfont.Face7x13
+	tests := []struct {
+		text  string
+		width int
+		want  []string
+	}{
+		{"", 70, []string{""}},
+		{"Black Friday ends in", 140, []string{"Black Friday ends in"}},
+		{"Black Friday ends in", 100, []string{"Black Friday", "ends in"}},
+		{"Black  Friday\nends in", 200, []string{"Black Friday", "ends in"}},
+		{"Extraordinary sale", 35, []string{"Extraordinary", "sale"}},
+	}
+
+	for _, tt := range tests {
+		got := wrapText(face, tt.text, fixed.I(tt.width))
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("wrapText(%q, %d) = %q, want %q", tt.text, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestSplitSpriteSheet(t *testing.T) {
+	// cell i has i%3+1 opaque columns starting at the second one, the last cell is empty
+	sheet := image.NewRGBA(image.Rect(0, 0, 11*5, 3))
+	for i := 0; i < 10; i++ {
+		r := image.Rect(i*5+1, 0, i*5+2+i%3, 3)
+		draw.Draw(sheet, r, image.NewUniform(color.White), image.Point{}, draw.Src)
+	}
+
+	images, err := splitSpriteSheet(sheet)
+	if err != nil {
+		t.Fatalf("splitSpriteSheet() error = %v", err)
+	}
+	if _, ok := images[':']; ok {
+		t.Errorf("splitSpriteSheet() has image for empty cell")
+	}
+	for i, r := range "0123456789" {
+		img, ok := images[r]
+		if !ok {
+			t.Fatalf("splitSpriteSheet() has no image for %q", r)
+		}
+		if got, want := img.Bounds().Size(), image.Pt(i%3+1, 3); got != want {
+			t.Errorf("splitSpriteSheet() image %q size = %v, want %v", r, got, want)
+		}
+	}
+
+	if _, err := splitSpriteSheet(image.NewRGBA(image.Rect(0, 0, 12, 3))); err == nil {
+		t.Errorf("splitSpriteSheet() expected error for width not divisible by glyphs")
+	}
+}
+
+func TestLoadSpriteDir(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"0.png", "colon.png"} {
+		f, err := os.Create(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := png.Encode(f, image.NewRGBA(image.Rect(0, 0, 4, 6))); err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+	}
+
+	images, err := loadSpriteDir(dir)
+	if err != nil {
+		t.Fatalf("loadSpriteDir() error = %v", err)
+	}
+	if len(images) != 2 || images['0'] == nil || images[':'] == nil {
+		t.Errorf("loadSpriteDir() = %v, want images for '0' and ':'", images)
+	}
+
+	if _, err := loadSpriteDir(t.TempDir()); err == nil {
+		t.Errorf("loadSpriteDir() expected error for empty directory")
+	}
+}
+
+func TestCheckGlyphs(t *testing.T) {
+	_, err := NewGenerator(
+		WithFormat("{m} мин {ss}s"),
+		WithLabels("d,h,минуты,s"),
+		WithTitle("Sale ends"),
+	)
+
+	var missing *MissingGlyphsError
+	if !errors.As(err, &missing) {
+		t.Fatalf("NewGenerator() error = %v, want MissingGlyphsError", err)
+	}
+	want := map[string][]rune{
+		"timer":  []rune("имн"),
+		"labels": []rune("имнтуы"),
+	}
+	if !reflect.DeepEqual(missing.Runes, want) {
+		t.Errorf("MissingGlyphsError.Runes = %q, want %q", missing.Runes, want)
+	}
+
+	_, err = NewGenerator(
+		WithFormat("{m} мин {ss}s"),
+		WithLabels("d,h,минуты,s"),
+		WithFallbackFontOpenTypeData(gobold.TTF),
+	)
+	if err != nil {
+		t.Errorf("NewGenerator() with fallback font error = %v", err)
+	}
+}
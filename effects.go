@@ -0,0 +1,134 @@
+package countdown
+
+import (
+	"image"
+	"image/draw"
+	"math"
+)
+
+// textEffects reports whether text is outlined or casts a shadow,
+// so it has to be drawn on a separate layer first
+func (g *Generator) textEffects() bool {
+	return g.StrokeWidth > 0 || g.ShadowColor != nil
+}
+
+// drawTextEffects draws the text layer on dst with the shadow and stroke
+// built from the alpha mask of the rasterized glyphs
+func (g *Generator) drawTextEffects(dst draw.Image, text *image.RGBA) {
+	mask := alphaMask(text)
+	if g.StrokeWidth > 0 {
+		mask = dilate(mask, g.StrokeWidth)
+	}
+
+	if g.ShadowColor != nil {
+		shadow := blur(mask, g.ShadowBlur)
+		offset := image.Pt(g.ShadowOffsetX, g.ShadowOffsetY)
+		draw.DrawMask(dst, dst.Bounds(), image.NewUniform(g.ShadowColor), image.Point{}, shadow, dst.Bounds().Min.Sub(offset), draw.Over)
+	}
+
+	if g.StrokeWidth > 0 {
+		draw.DrawMask(dst, dst.Bounds(), image.NewUniform(g.StrokeColor), image.Point{}, mask, dst.Bounds().Min, draw.Over)
+	}
+
+	draw.Draw(dst, dst.Bounds(), text, dst.Bounds().Min, draw.Over)
+}
+
+// alphaMask returns the alpha channel of the image
+func alphaMask(img *image.RGBA) *image.Alpha {
+	mask := image.NewAlpha(img.Bounds())
+	for i := range mask.Pix {
+		mask.Pix[i] = img.Pix[i*4+3]
+	}
+	return mask
+}
+
+// dilate grows the mask by radius pixels in every direction,
+// stamping an anti-aliased disk around every covered pixel
+func dilate(mask *image.Alpha, radius int) *image.Alpha {
+	type offset struct {
+		dx, dy   int
+		coverage float64
+	}
+
+	var disk []offset
+	for dy := -radius; dy <= radius; dy++ {
+		for dx := -radius; dx <= radius; dx++ {
+			dist := math.Hypot(float64(dx), float64(dy))
+			coverage := min(1, float64(radius)+0.5-dist)
+			if coverage > 0 {
+				disk = append(disk, offset{dx, dy, coverage})
+			}
+		}
+	}
+
+	b := mask.Bounds()
+	out := image.NewAlpha(b)
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			a := mask.Pix[y*mask.Stride+x]
+			if a == 0 {
+				continue
+			}
+
+			for _, o := range disk {
+				px, py := x+o.dx, y+o.dy
+				if px < 0 || py < 0 || px >= b.Dx() || py >= b.Dy() {
+					continue
+				}
+				v := uint8(float64(a) * o.coverage)
+				if i := py*out.Stride + px; out.Pix[i] < v {
+					out.Pix[i] = v
+				}
+			}
+		}
+	}
+	return out
+}
+
+// blur approximates gaussian blur of the mask with three passes of box blur
+func blur(mask *image.Alpha, radius int) *image.Alpha {
+	r := (radius + 1) / 2
+	if r == 0 {
+		return mask
+	}
+
+	b := mask.Bounds()
+	w, h := b.Dx(), b.Dy()
+	src := make([]uint8, len(mask.Pix))
+	copy(src, mask.Pix)
+	tmp := make([]uint8, len(src))
+
+	for range 3 {
+		boxBlur(src, tmp, w, h, 1, w, r) // rows
+		boxBlur(tmp, src, h, w, w, 1, r) // columns
+	}
+
+	out := image.NewAlpha(b)
+	copy(out.Pix, src)
+	return out
+}
+
+// boxBlur averages n lines of length values in a window of 2*r+1,
+// step is the distance between values of a line, stride between lines.
+// Values outside of the line are transparent.
+func boxBlur(src, dst []uint8, length, n, step, stride, r int) {
+	window := 2*r + 1
+	for line := 0; line < n; line++ {
+		start := line * stride
+
+		sum := 0
+		for i := 0; i < min(r, length); i++ {
+			sum += int(src[start+i*step])
+		}
+
+		for i := 0; i < length; i++ {
+			if j := i + r; j < length {
+				sum += int(src[start+j*step])
+			}
+			if j := i - r - 1; j >= 0 {
+				sum -= int(src[start+j*step])
+			}
+			dst[start+i*step] = uint8(sum / window)
+		}
+	}
+}
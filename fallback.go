@@ -0,0 +1,175 @@
+package countdown
+
+import (
+	"fmt"
+	"image"
+	"maps"
+	"slices"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// FallbackFace draws every rune with the first face that has its glyph,
+// runes missing in all faces are drawn with the first one.
+// Metrics are of the first face.
+type FallbackFace []font.Face
+
+// index returns the index of the face drawing r
+func (f FallbackFace) index(r rune) int {
+	for i, face := range f {
+		if _, ok := face.GlyphAdvance(r); ok {
+			return i
+		}
+	}
+	return 0
+}
+
+func (f FallbackFace) Close() error {
+	return nil
+}
+
+func (f FallbackFace) Glyph(dot fixed.Point26_6, r rune) (dr image.Rectangle, mask image.Image, maskp image.Point, advance fixed.Int26_6, ok bool) {
+	return f[f.index(r)].Glyph(dot, r)
+}
+
+func (f FallbackFace) GlyphBounds(r rune) (bounds fixed.Rectangle26_6, advance fixed.Int26_6, ok bool) {
+	return f[f.index(r)].GlyphBounds(r)
+}
+
+func (f FallbackFace) GlyphAdvance(r rune) (advance fixed.Int26_6, ok bool) {
+	return f[f.index(r)].GlyphAdvance(r)
+}
+
+// Kern returns kerning of runes of the same face, there is none between faces
+func (f FallbackFace) Kern(r0, r1 rune) fixed.Int26_6 {
+	i := f.index(r0)
+	if i != f.index(r1) {
+		return 0
+	}
+	return f[i].Kern(r0, r1)
+}
+
+func (f FallbackFace) Metrics() font.Metrics {
+	return f[0].Metrics()
+}
+
+// withFallback returns the face followed by fallback fonts of the same size
+func (g *Generator) withFallback(face font.Face, size float64) (font.Face, error) {
+	if len(g.FallbackFonts) == 0 {
+		return face, nil
+	}
+
+	faces := FallbackFace{face}
+	for _, f := range g.FallbackFonts {
+		fallback, err := newFace(f, size)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create fallback font face: %v", err)
+		}
+		faces = append(faces, fallback)
+	}
+	return faces, nil
+}
+
+// MissingGlyphsError is returned by NewGenerator when fonts have no glyphs
+// for runes that can appear in frames, e.g. Cyrillic labels with a Latin font.
+// Fonts with the glyphs can be added with WithFallbackFontPath.
+type MissingGlyphsError struct {
+	// Runes are missing runes by the text drawn with the font:
+	// "timer", "labels", "title" or "subtitle"
+	Runes map[string][]rune
+}
+
+func (e *MissingGlyphsError) Error() string {
+	var list []string
+	for _, name := range slices.Sorted(maps.Keys(e.Runes)) {
+		list = append(list, fmt.Sprintf("%s %q", name, string(e.Runes[name])))
+	}
+	return "fonts have no glyphs for " + strings.Join(list, ", ")
+}
+
+// checkGlyphs returns MissingGlyphsError if fonts can't draw any of the runes
+// that can appear in frames
+func (g *Generator) checkGlyphs() error {
+	var labels strings.Builder
+	for _, forms := range g.Labels {
+		for _, form := range forms {
+			labels.WriteString(form)
+		}
+	}
+
+	missing := map[string][]rune{}
+	for _, t := range []struct {
+		name string
+		face font.Face
+		text string
+	}{
+		{"timer", g.FontFace, g.timerRunes()},
+		{"labels", g.LabelFontFace, labels.String()},
+		{"title", g.Title.FontFace, blockRunes(g.Title.Text)},
+		{"subtitle", g.Subtitle.FontFace, blockRunes(g.Subtitle.Text)},
+	} {
+		var runes []rune
+		for _, r := range t.text {
+			if _, ok := t.face.GlyphAdvance(r); !ok && !slices.Contains(runes, r) {
+				runes = append(runes, r)
+			}
+		}
+		if len(runes) > 0 {
+			slices.Sort(runes)
+			missing[t.name] = runes
+		}
+	}
+
+	if len(missing) > 0 {
+		return &MissingGlyphsError{Runes: missing}
+	}
+	return nil
+}
+
+// timerRunes returns text of all runes the timer font can draw:
+// digits, literal text of the format or separators, and the expired text
+func (g *Generator) timerRunes() string {
+	var b strings.Builder
+	b.WriteString(localizeDigits("0123456789", g.ZeroDigit))
+	b.WriteString(g.ExpiredText)
+
+	if g.Format != "" {
+		// format is validated by WithFormat
+		format, _ := parseFormat(g.Format)
+		for _, t := range format {
+			b.WriteString(t.text)
+		}
+		return b.String()
+	}
+
+	units := g.Units
+	if units == 0 {
+		units = UnitDays | UnitHours | UnitMinutes | UnitSeconds
+	}
+	format := defaultFormat(units, false)
+	for i, t := range format {
+		if t.unit != 0 {
+			continue
+		}
+		s, ok := g.Separators[format[i-1].unit]
+		switch {
+		case ok && s.Image != nil:
+		case ok && s.Text != "":
+			b.WriteString(s.Text)
+		default:
+			b.WriteString(t.text)
+		}
+	}
+	if g.FractionDigits > 0 && !g.CountUp {
+		b.WriteString(".")
+	}
+	return b.String()
+}
+
+// blockRunes returns runes of the text block as they are drawn:
+// words separated by single spaces
+func blockRunes(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,180 @@
+package countdown
+
+import (
+	"image"
+	"image/draw"
+	"math"
+)
+
+// Fit is a way to fit the background image into the frame.
+type Fit int
+
+const (
+	// FitNone draws the image as is at the top left corner
+	FitNone Fit = iota
+	// FitCover scales the image to cover the whole frame, cropping it
+	FitCover
+	// FitContain scales the image to fit into the frame, leaving empty space
+	FitContain
+	// FitStretch scales the image to the frame size, ignoring aspect ratio
+	FitStretch
+	// FitTile repeats the image to fill the frame
+	FitTile
+	// FitCenter places the image at the anchor without scaling
+	FitCenter
+)
+
+// Anchor is a point of a box something is aligned to.
+type Anchor int
+
+const (
+	AnchorCenter Anchor = iota
+	AnchorTopLeft
+	AnchorTop
+	AnchorTopRight
+	AnchorLeft
+	AnchorRight
+	AnchorBottomLeft
+	AnchorBottom
+	AnchorBottomRight
+)
+
+// factors returns the position of the anchor in halves of the box size:
+// 0 is left (top), 1 is center, 2 is right (bottom)
+func (a Anchor) factors() (fx, fy int) {
+	switch a {
+	case AnchorTopLeft:
+		return 0, 0
+	case AnchorTop:
+		return 1, 0
+	case AnchorTopRight:
+		return 2, 0
+	case AnchorLeft:
+		return 0, 1
+	case AnchorRight:
+		return 2, 1
+	case AnchorBottomLeft:
+		return 0, 2
+	case AnchorBottom:
+		return 1, 2
+	case AnchorBottomRight:
+		return 2, 2
+	default:
+		return 1, 1
+	}
+}
+
+// position returns the top left corner of the box of size
+// aligned to the anchor inside of outer
+func (a Anchor) position(outer image.Rectangle, size image.Point) image.Point {
+	fx, fy := a.factors()
+	return image.Point{
+		X: outer.Min.X + (outer.Dx()-size.X)*fx/2,
+		Y: outer.Min.Y + (outer.Dy()-size.Y)*fy/2,
+	}
+}
+
+// fitImage returns the image fitted into the frame of size w×h,
+// placed at the anchor and moved by the offset
+func fitImage(src image.Image, w, h int, fit Fit, anchor Anchor, offset image.Point) image.Image {
+	if fit == FitNone {
+		return src
+	}
+
+	frame := image.Rect(0, 0, w, h)
+	size := src.Bounds().Size()
+	sx, sy := float64(w)/float64(size.X), float64(h)/float64(size.Y)
+
+	switch fit {
+	case FitCover:
+		s := math.Max(sx, sy)
+		size = image.Pt(int(math.Ceil(float64(size.X)*s)), int(math.Ceil(float64(size.Y)*s)))
+	case FitContain:
+		s := math.Min(sx, sy)
+		size = image.Pt(int(math.Round(float64(size.X)*s)), int(math.Round(float64(size.Y)*s)))
+	case FitStretch:
+		size = frame.Size()
+	}
+
+	img := src
+	if size != src.Bounds().Size() {
+		img = resample(src, size.X, size.Y)
+	}
+
+	dst := image.NewRGBA(frame)
+	at := anchor.position(frame, size).Add(offset)
+
+	if fit != FitTile {
+		draw.Draw(dst, image.Rectangle{at, at.Add(size)}, img, img.Bounds().Min, draw.Src)
+		return dst
+	}
+
+	// start from the tile before the frame, so tiles cover it completely
+	startX := at.X - (at.X+size.X-1)/size.X*size.X
+	startY := at.Y - (at.Y+size.Y-1)/size.Y*size.Y
+	for y := startY; y < h; y += size.Y {
+		for x := startX; x < w; x += size.X {
+			r := image.Rectangle{image.Pt(x, y), image.Pt(x, y).Add(size)}
+			draw.Draw(dst, r, img, img.Bounds().Min, draw.Src)
+		}
+	}
+	return dst
+}
+
+// resample scales the image to w×h with a triangle filter,
+// which is bilinear when upscaling and averages pixels when downscaling
+func resample(src image.Image, w, h int) *image.RGBA {
+	b := src.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), src, b.Min, draw.Src)
+
+	// scale rows first, then columns
+	rows := image.NewRGBA(image.Rect(0, 0, w, b.Dy()))
+	resampleLines(rgba.Pix, rows.Pix, b.Dx(), w, b.Dy(), 4, rgba.Stride, 4, rows.Stride)
+
+	out := image.NewRGBA(image.Rect(0, 0, w, h))
+	resampleLines(rows.Pix, out.Pix, b.Dy(), h, w, rows.Stride, 4, out.Stride, 4)
+	return out
+}
+
+// resampleLines scales n lines of RGBA pixels from length to size.
+// Steps are distances between pixels of a line, strides are distances between lines.
+func resampleLines(src, dst []uint8, length, size, n, srcStep, srcStride, dstStep, dstStride int) {
+	scale := float64(size) / float64(length)
+	support := math.Max(1, 1/scale) // filter radius in source pixels
+
+	type weight struct {
+		j int
+		w float64
+	}
+
+	for i := 0; i < size; i++ {
+		center := (float64(i)+0.5)/scale - 0.5
+		lo := max(0, int(math.Floor(center-support)))
+		hi := min(length-1, int(math.Ceil(center+support)))
+
+		var weights []weight
+		var total float64
+		for j := lo; j <= hi; j++ {
+			if w := 1 - math.Abs(float64(j)-center)/support; w > 0 {
+				weights = append(weights, weight{j, w})
+				total += w
+			}
+		}
+
+		for line := 0; line < n; line++ {
+			var sum [4]float64
+			for _, wt := range weights {
+				p := line*srcStride + wt.j*srcStep
+				for c := range sum {
+					sum[c] += float64(src[p+c]) * wt.w
+				}
+			}
+
+			o := line*dstStride + i*dstStep
+			for c := range sum {
+				dst[o+c] = uint8(math.Min(255, sum[c]/total+0.5))
+			}
+		}
+	}
+}
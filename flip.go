@@ -0,0 +1,173 @@
+package countdown
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+// transitionDelays splits the first part of the frame delay into delays of transition frames.
+// Frames shorter than 2/100 of a second are dropped, as browsers slow them down,
+// and the final frame is left at least as long.
+func transitionDelays(delay, frames int, duration float64) []int {
+	total := min(int(float64(delay)*duration+0.5), delay-2)
+	n := min(frames, total/2)
+	if n < 1 {
+		return nil
+	}
+
+	delays := make([]int, n)
+	for i := range delays {
+		delays[i] = (i+1)*total/n - i*total/n
+	}
+	return delays
+}
+
+// cardGap returns the gap between flip clock cards of the same part
+func (g *Generator) cardGap() int {
+	return g.CardPadding / 2
+}
+
+// cellRect returns bounds of the digit cell: the card in flip clock mode,
+// or the line box of the font otherwise
+func (g *Generator) cellRect(c cell, y fixed.Int26_6) image.Rectangle {
+	m := g.FontFace.Metrics()
+	top, bottom := y-m.Ascent, y+m.Descent
+	if g.FlipClock {
+		top = y - fixed.I(m.CapHeight.Ceil()+g.CardPadding)
+		bottom = y + fixed.I(g.CardPadding)
+	}
+	return image.Rect(c.x.Floor(), top.Floor(), (c.x + c.width).Ceil(), bottom.Ceil())
+}
+
+// renderCell draws the digit of the cell on a transparent image,
+// with a rounded card behind it in flip clock mode
+func (g *Generator) renderCell(d *font.Drawer, c cell, y fixed.Int26_6) *image.RGBA {
+	r := g.cellRect(c, y)
+	img := image.NewRGBA(r)
+
+	if g.FlipClock {
+		fillRoundedRect(img, r, g.CardRadius, g.CardColor)
+	}
+
+	cd := *d
+	cd.Dst = img
+	cd.Dot = fixed.Point26_6{
+		X: c.x + (c.width-d.MeasureString(c.text))/2,
+		Y: y,
+	}
+	drawString(&cd, c.text)
+
+	if g.FlipClock {
+		// split line between the top and bottom halves of the card
+		mid := r.Min.Y + r.Dy()/2
+		draw.Draw(img, image.Rect(r.Min.X, mid, r.Max.X, mid+1), image.Transparent, image.Point{}, draw.Src)
+	}
+
+	return img
+}
+
+// drawTransition draws the cell changing from prev to next digit, p is the progress from 0 to 1
+func (g *Generator) drawTransition(d *font.Drawer, prev, next cell, y fixed.Int26_6, p float64) {
+	from := g.renderCell(d, prev, y)
+	to := g.renderCell(d, next, y)
+
+	switch g.Transition {
+	case TransitionFlip:
+		drawFlip(d.Dst, from, to, p)
+	case TransitionSlide:
+		drawSlide(d.Dst, from, to, p)
+	case TransitionFade:
+		drawFade(d.Dst, from, to, p)
+	case TransitionRoll:
+		drawRoll(d.Dst, from, to, p)
+	default:
+		draw.Draw(d.Dst, to.Bounds(), to, to.Bounds().Min, draw.Over)
+	}
+}
+
+// drawFlip draws the split-flap animation: the top half of the old card
+// folds down to the middle, then the bottom half of the new card unfolds from it
+func drawFlip(dst draw.Image, from, to *image.RGBA, p float64) {
+	r := from.Bounds()
+	mid := r.Min.Y + r.Dy()/2
+	top := image.Rect(r.Min.X, r.Min.Y, r.Max.X, mid)
+	bottom := image.Rect(r.Min.X, mid, r.Max.X, r.Max.Y)
+
+	// new top half is revealed behind the flap,
+	// old bottom half stays until the flap covers it
+	draw.Draw(dst, top, to, top.Min, draw.Over)
+	draw.Draw(dst, bottom, from, bottom.Min, draw.Over)
+
+	if p < 0.5 {
+		h := int(float64(top.Dy()) * (1 - 2*p))
+		drawScaledRows(dst, image.Rect(top.Min.X, mid-h, top.Max.X, mid), from, top)
+		return
+	}
+
+	h := int(float64(bottom.Dy()) * (2*p - 1))
+	drawScaledRows(dst, image.Rect(bottom.Min.X, mid, bottom.Max.X, mid+h), to, bottom)
+}
+
+// drawSlide moves the old cell up by the share p of its height,
+// the new cell follows it from below, both clipped to the cell bounds
+func drawSlide(dst draw.Image, from, to *image.RGBA, p float64) {
+	r := from.Bounds()
+	offset := int(float64(r.Dy()) * p)
+	draw.Draw(dst, r, from, image.Pt(r.Min.X, r.Min.Y+offset), draw.Over)
+	draw.Draw(dst, r, to, image.Pt(r.Min.X, r.Min.Y+offset-r.Dy()), draw.Over)
+}
+
+// drawFade blends the new cell over the old one with opacity p
+func drawFade(dst draw.Image, from, to *image.RGBA, p float64) {
+	r := from.Bounds()
+	a := uint8(255 * p)
+	draw.DrawMask(dst, r, from, r.Min, image.NewUniform(color.Alpha{255 - a}), image.Point{}, draw.Over)
+	draw.DrawMask(dst, r, to, r.Min, image.NewUniform(color.Alpha{a}), image.Point{}, draw.Over)
+}
+
+// drawRoll squeezes the old cell to the top edge while the new one
+// stretches from the bottom edge, as if both were printed on a rotating drum
+func drawRoll(dst draw.Image, from, to *image.RGBA, p float64) {
+	r := from.Bounds()
+	// drum speeds up and slows down, so the movement is eased
+	split := r.Max.Y - int(float64(r.Dy())*(1-math.Cos(p*math.Pi))/2)
+	drawScaledRows(dst, image.Rect(r.Min.X, r.Min.Y, r.Max.X, split), from, r)
+	drawScaledRows(dst, image.Rect(r.Min.X, split, r.Max.X, r.Max.Y), to, r)
+}
+
+// drawScaledRows draws sr of src into dr of dst of the same width,
+// scaling it vertically with nearest neighbor sampling
+func drawScaledRows(dst draw.Image, dr image.Rectangle, src image.Image, sr image.Rectangle) {
+	for y := dr.Min.Y; y < dr.Max.Y; y++ {
+		sy := sr.Min.Y + (y-dr.Min.Y)*sr.Dy()/dr.Dy()
+		draw.Draw(dst, image.Rect(dr.Min.X, y, dr.Max.X, y+1), src, image.Pt(sr.Min.X, sy), draw.Over)
+	}
+}
+
+// fillRoundedRect fills r with anti-aliased rounded corners of the given radius
+func fillRoundedRect(dst draw.Image, r image.Rectangle, radius int, c color.Color) {
+	w, h := float32(r.Dx()), float32(r.Dy())
+	rad := float32(min(radius, r.Dx()/2, r.Dy()/2))
+
+	// control points offset to approximate a quarter of a circle with a cubic curve
+	k := rad * 0.5523
+
+	z := vector.NewRasterizer(r.Dx(), r.Dy())
+	z.MoveTo(rad, 0)
+	z.LineTo(w-rad, 0)
+	z.CubeTo(w-rad+k, 0, w, rad-k, w, rad)
+	z.LineTo(w, h-rad)
+	z.CubeTo(w, h-rad+k, w-rad+k, h, w-rad, h)
+	z.LineTo(rad, h)
+	z.CubeTo(rad-k, h, 0, h-rad+k, 0, h-rad)
+	z.LineTo(0, rad)
+	z.CubeTo(0, rad-k, rad-k, 0, rad, 0)
+	z.ClosePath()
+	z.Draw(dst, r, image.NewUniform(c), image.Point{})
+}
@@ -0,0 +1,151 @@
+package countdown
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// token is a piece of the countdown format:
+// either literal text or a field replaced with the value of a unit
+type token struct {
+	text  string // literal text
+	unit  Unit   // unit of the field, zero for literal text
+	width int    // minimal number of digits of the field, padded with zeros
+}
+
+// parseFormat parses templates like "{d}d {hh}h {mm}m" or "T-{h}:{mm}:{ss}".
+// Fields {d}, {h}, {m} and {s} are replaced with days, hours, minutes and seconds,
+// repeated letters pad the value with zeros, e.g. {mm} is always two digits.
+// Field {f} is a fraction of a second, repeated letters add precision:
+// {f} is tenths, {ff} is hundredths.
+// Use "{{" and "}}" for literal braces.
+func parseFormat(format string) ([]token, error) {
+	var (
+		tokens  []token
+		literal strings.Builder
+		units   Unit
+	)
+
+	flush := func() {
+		if literal.Len() > 0 {
+			tokens = append(tokens, token{text: literal.String()})
+			literal.Reset()
+		}
+	}
+
+	for i := 0; i < len(format); i++ {
+		switch {
+		case strings.HasPrefix(format[i:], "{{"):
+			literal.WriteByte('{')
+			i++
+		case strings.HasPrefix(format[i:], "}}"):
+			literal.WriteByte('}')
+			i++
+		case format[i] == '{':
+			end := strings.IndexByte(format[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed field at position %d", i)
+			}
+
+			name := format[i+1 : i+end]
+			unit, err := parseField(name)
+			if err != nil {
+				return nil, err
+			}
+
+			flush()
+			tokens = append(tokens, token{unit: unit, width: len(name)})
+			units |= unit
+			i += end
+		case format[i] == '}':
+			return nil, fmt.Errorf("unexpected } at position %d", i)
+		default:
+			literal.WriteByte(format[i])
+		}
+	}
+	flush()
+
+	if units == 0 {
+		return nil, fmt.Errorf("format has no fields")
+	}
+
+	return tokens, nil
+}
+
+// parseField returns unit of the field name like "mm" or "f"
+func parseField(name string) (Unit, error) {
+	if name == "" || strings.Count(name, name[:1]) != len(name) {
+		return 0, fmt.Errorf("unknown field {%s}", name)
+	}
+
+	if name[0] == 'f' {
+		return UnitFraction, nil
+	}
+
+	unit, err := parseUnits(name[:1])
+	if err != nil {
+		return 0, fmt.Errorf("unknown field {%s}", name)
+	}
+	return unit, nil
+}
+
+// defaultFormat returns format of colon-separated units, e.g. {hh}:{mm}:{ss}
+func defaultFormat(units Unit, noLeadingZeros bool) []token {
+	var tokens []token
+	for _, u := range unitDurations {
+		if units&u.unit == 0 {
+			continue
+		}
+
+		width := 2
+		if len(tokens) == 0 && noLeadingZeros {
+			width = 1
+		}
+		if len(tokens) > 0 {
+			tokens = append(tokens, token{text: ":"})
+		}
+		tokens = append(tokens, token{unit: u.unit, width: width})
+	}
+	return tokens
+}
+
+// formatTemplate replaces fields of the format with values of the duration
+func formatTemplate(d time.Duration, tokens []token) []segment {
+	var units Unit
+	for _, t := range tokens {
+		units |= t.unit
+	}
+
+	values := map[Unit]int{}
+	for _, s := range splitTime(d, units) {
+		values[s.unit] = s.value
+	}
+
+	segments := make([]segment, len(tokens))
+	for i, t := range tokens {
+		if t.unit == 0 {
+			segments[i] = segment{text: t.text}
+			continue
+		}
+
+		v := values[t.unit]
+		if t.unit == UnitFraction {
+			v = int((d % time.Second) * time.Duration(pow10(t.width)) / time.Second)
+		}
+		segments[i] = segment{
+			unit:  t.unit,
+			value: v,
+			text:  fmt.Sprintf("%0*d", t.width, v),
+		}
+	}
+	return segments
+}
+
+func pow10(n int) int {
+	p := 1
+	for i := 0; i < n; i++ {
+		p *= 10
+	}
+	return p
+}
@@ -0,0 +1,217 @@
+package countdown
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/font"
+)
+
+// Gradient is an image of colors interpolated between stops,
+// it can be used as a background or as a text source.
+// Like image.Uniform, it has infinite bounds: colors outside of Rect
+// are the colors of the first or the last stop.
+type Gradient struct {
+	Radial bool
+	Circle bool    // radial gradient is a circle instead of an ellipse
+	Angle  float64 // direction of the linear gradient in degrees, 0 is to top, 90 is to right
+	Stops  []GradientStop
+	Rect   image.Rectangle // area the gradient spans
+}
+
+type GradientStop struct {
+	Offset float64 // position from 0 to 1
+	Color  color.Color
+}
+
+// In returns a copy of the gradient spanning r
+func (gr *Gradient) In(r image.Rectangle) *Gradient {
+	c := *gr
+	c.Rect = r
+	return &c
+}
+
+func (gr *Gradient) ColorModel() color.Model {
+	return color.RGBA64Model
+}
+
+func (gr *Gradient) Bounds() image.Rectangle {
+	return image.Rectangle{Min: image.Point{-1e9, -1e9}, Max: image.Point{1e9, 1e9}}
+}
+
+func (gr *Gradient) At(x, y int) color.Color {
+	return gr.colorAt(gr.position(float64(x)+0.5, float64(y)+0.5))
+}
+
+// position returns the offset of the point on the gradient line
+func (gr *Gradient) position(x, y float64) float64 {
+	w, h := float64(gr.Rect.Dx()), float64(gr.Rect.Dy())
+	dx := x - float64(gr.Rect.Min.X) - w/2
+	dy := y - float64(gr.Rect.Min.Y) - h/2
+
+	if gr.Radial {
+		// ending shape touches the farthest corner, like in CSS
+		if gr.Circle {
+			return math.Hypot(dx, dy) / math.Hypot(w/2, h/2)
+		}
+		return math.Hypot(dx/(w/2), dy/(h/2)) / math.Sqrt2
+	}
+
+	// gradient line goes through the center in the direction of the angle,
+	// its length is chosen so the corners get the colors of the first and last stops
+	a := gr.Angle * math.Pi / 180
+	sin, cos := math.Sin(a), math.Cos(a)
+	length := math.Abs(w*sin) + math.Abs(h*cos)
+	return (dx*sin-dy*cos)/length + 0.5
+}
+
+// colorAt interpolates colors of the stops at the offset t
+func (gr *Gradient) colorAt(t float64) color.Color {
+	stops := gr.Stops
+	if t <= stops[0].Offset {
+		return stops[0].Color
+	}
+	for i := 1; i < len(stops); i++ {
+		if t > stops[i].Offset {
+			continue
+		}
+
+		a, b := stops[i-1], stops[i]
+		if b.Offset == a.Offset {
+			return b.Color
+		}
+		return mixColors(a.Color, b.Color, (t-a.Offset)/(b.Offset-a.Offset))
+	}
+	return stops[len(stops)-1].Color
+}
+
+// mixColors linearly interpolates premultiplied colors
+func mixColors(a, b color.Color, t float64) color.Color {
+	ar, ag, ab, aa := a.RGBA()
+	br, bg, bb, ba := b.RGBA()
+	mix := func(x, y uint32) uint16 {
+		return uint16(float64(x) + (float64(y)-float64(x))*t + 0.5)
+	}
+	return color.RGBA64{mix(ar, br), mix(ag, bg), mix(ab, bb), mix(aa, ba)}
+}
+
+// drawString draws s like font.Drawer.DrawString does,
+// but sources colors at the same points as the destination,
+// so gradients span the whole text instead of restarting at every glyph
+func drawString(d *font.Drawer, s string) {
+	prev := rune(-1)
+	for _, r := range s {
+		if prev >= 0 {
+			d.Dot.X += d.Face.Kern(prev, r)
+		}
+		dr, mask, maskp, advance, _ := d.Face.Glyph(d.Dot, r)
+		if img, ok := sprite(d.Face, r); ok {
+			// sprites keep their own colors
+			draw.Draw(d.Dst, dr, img, maskp, draw.Over)
+		} else if !dr.Empty() {
+			draw.DrawMask(d.Dst, dr, d.Src, dr.Min, mask, maskp, draw.Over)
+		}
+		d.Dot.X += advance
+		prev = r
+	}
+}
+
+// parseGradient parses CSS-like gradients:
+// "linear-gradient(90deg, red, blue)", "linear-gradient(to right, red, #ff0 20%, blue)",
+// "radial-gradient(white, black)" or "radial-gradient(circle, white, black 80%)"
+func parseGradient(s string) (*Gradient, error) {
+	name, args, ok := strings.Cut(strings.TrimSpace(s), "(")
+	if !ok || !strings.HasSuffix(args, ")") {
+		return nil, fmt.Errorf("expected gradient(...), got %q", s)
+	}
+
+	gr := &Gradient{Angle: 180}
+	switch name {
+	case "linear-gradient":
+	case "radial-gradient":
+		gr.Radial = true
+	default:
+		return nil, fmt.Errorf("unknown gradient %q", name)
+	}
+
+	list := strings.Split(strings.TrimSuffix(args, ")"), ",")
+	for i := range list {
+		list[i] = strings.TrimSpace(list[i])
+	}
+
+	// optional direction or shape goes first
+	switch first := list[0]; {
+	case !gr.Radial && strings.HasSuffix(first, "deg"):
+		angle, err := strconv.ParseFloat(strings.TrimSuffix(first, "deg"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse angle: %v", err)
+		}
+		gr.Angle = angle
+		list = list[1:]
+	case !gr.Radial && strings.HasPrefix(first, "to "):
+		angle, ok := map[string]float64{"top": 0, "right": 90, "bottom": 180, "left": 270}[strings.TrimSpace(first[3:])]
+		if !ok {
+			return nil, fmt.Errorf("unknown direction %q", first)
+		}
+		gr.Angle = angle
+		list = list[1:]
+	case gr.Radial && (first == "circle" || first == "ellipse"):
+		gr.Circle = first == "circle"
+		list = list[1:]
+	}
+
+	if len(list) < 2 {
+		return nil, fmt.Errorf("expected at least 2 color stops, got %d", len(list))
+	}
+
+	// stops without offsets are spread evenly between their neighbors
+	gr.Stops = make([]GradientStop, len(list))
+	known := make([]bool, len(list))
+	for i, item := range list {
+		c, offset, hasOffset := strings.Cut(item, " ")
+
+		col, err := parseColor(c)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse color %q: %v", c, err)
+		}
+		gr.Stops[i].Color = col
+
+		if hasOffset {
+			percent, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(offset), "%"), 64)
+			if err != nil || !strings.HasSuffix(offset, "%") {
+				return nil, fmt.Errorf("invalid stop offset %q", offset)
+			}
+			gr.Stops[i].Offset = percent / 100
+			known[i] = true
+		}
+	}
+
+	last := len(list) - 1
+	if !known[0] {
+		gr.Stops[0].Offset, known[0] = 0, true
+	}
+	if !known[last] {
+		gr.Stops[last].Offset, known[last] = 1, true
+	}
+
+	prev := 0
+	for i := 1; i <= last; i++ {
+		if !known[i] {
+			continue
+		}
+		for j := prev + 1; j < i; j++ {
+			a, b := gr.Stops[prev].Offset, gr.Stops[i].Offset
+			gr.Stops[j].Offset = a + (b-a)*float64(j-prev)/float64(i-prev)
+		}
+		// offsets can't go backwards
+		gr.Stops[i].Offset = max(gr.Stops[i].Offset, gr.Stops[prev].Offset)
+		prev = i
+	}
+
+	return gr, nil
+}
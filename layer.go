@@ -0,0 +1,104 @@
+package countdown
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"time"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// Layer draws something on every frame over the background,
+// layers are drawn in the order of Generator.Layers.
+type Layer interface {
+	Draw(dst draw.Image, state FrameState)
+}
+
+// FrameState describes the frame being drawn.
+type FrameState struct {
+	Value   time.Duration // value of the timer
+	Elapsed time.Duration // time from the start of the animation
+	Expired bool          // countdown reached zero
+
+	// Progress is the fraction of the countdown left, from 1 at TimeFrom to 0 when it expires,
+	// when counting up it grows from 0 to 1 at the last frame
+	Progress float64
+
+	// state of the generator to draw the countdown
+	g      *Generator
+	frame  frameState
+	d, ld  *font.Drawer
+	format []token
+}
+
+// LayerFunc is a function drawing a layer, e.g. a badge changing with the timer value.
+type LayerFunc func(dst draw.Image, state FrameState)
+
+func (f LayerFunc) Draw(dst draw.Image, state FrameState) {
+	f(dst, state)
+}
+
+// CountdownLayer draws the timer with labels, title and subtitle,
+// or the expired text, with text effects. Frames have only this layer
+// unless Generator.Layers is set.
+var CountdownLayer Layer = countdownLayer{}
+
+type countdownLayer struct{}
+
+func (countdownLayer) Draw(dst draw.Image, state FrameState) {
+	if state.g == nil {
+		// frame state is not made by the generator
+		return
+	}
+	state.g.drawTimer(dst, state.d, state.ld, state.format, state.frame)
+}
+
+// ImageLayer draws the image aligned to the anchor of the frame and moved by the offset,
+// e.g. a logo in the top right corner.
+type ImageLayer struct {
+	Image  image.Image
+	Anchor Anchor
+	Offset image.Point
+}
+
+func (l ImageLayer) Draw(dst draw.Image, state FrameState) {
+	b := l.Image.Bounds()
+	at := l.Anchor.position(dst.Bounds(), b.Size()).Add(l.Offset)
+	draw.Draw(dst, image.Rectangle{at, at.Add(b.Size())}, l.Image, b.Min, draw.Over)
+}
+
+// TextLayer draws a line of text aligned to the anchor of the frame and moved by the offset,
+// the text box spans from the cap height to the baseline like the timer.
+type TextLayer struct {
+	Text   string
+	Face   font.Face   // Face7x13 if nil
+	Color  color.Color // text color of the generator if nil
+	Anchor Anchor
+	Offset image.Point
+}
+
+func (l TextLayer) Draw(dst draw.Image, state FrameState) {
+	face := l.Face
+	if face == nil {
+		face = basicfont.Face7x13
+	}
+
+	c := l.Color
+	if c == nil && state.g != nil {
+		c = state.g.TextColor
+	}
+	if c == nil {
+		c = color.White
+	}
+
+	d := &font.Drawer{Dst: dst, Src: image.NewUniform(c), Face: face}
+	capHeight := face.Metrics().CapHeight.Ceil()
+	size := image.Pt(d.MeasureString(l.Text).Ceil(), capHeight)
+	at := l.Anchor.position(dst.Bounds(), size).Add(l.Offset)
+
+	d.Dot = fixed.P(at.X, at.Y+capHeight)
+	drawString(d, l.Text)
+}
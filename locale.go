@@ -0,0 +1,183 @@
+package countdown
+
+import "strings"
+
+// PluralCategory is a CLDR plural category.
+type PluralCategory int
+
+const (
+	PluralOne PluralCategory = iota
+	PluralFew
+	PluralMany
+	PluralOther
+)
+
+// PluralRule picks the plural form of a label for a number.
+type PluralRule struct {
+	// Categories lists categories the language uses, in CLDR order.
+	// Label forms are matched to categories by position,
+	// e.g. "день|дня|дней" for Russian one, few and many.
+	Categories []PluralCategory
+
+	// Select returns the category of a non-negative integer.
+	Select func(n int) PluralCategory
+}
+
+// form returns index of the label form to use for n
+func (r PluralRule) form(n int) int {
+	if r.Select == nil || len(r.Categories) == 0 {
+		return 0
+	}
+
+	category := r.Select(n)
+	for i, c := range r.Categories {
+		if c == category {
+			return i
+		}
+	}
+	return len(r.Categories) - 1
+}
+
+var (
+	ruleOneOther = PluralRule{
+		Categories: []PluralCategory{PluralOne, PluralOther},
+		Select: func(n int) PluralCategory {
+			if n == 1 {
+				return PluralOne
+			}
+			return PluralOther
+		},
+	}
+
+	// ruleZeroOneOther is used by languages where zero is singular too
+	ruleZeroOneOther = PluralRule{
+		Categories: []PluralCategory{PluralOne, PluralOther},
+		Select: func(n int) PluralCategory {
+			if n == 0 || n == 1 {
+				return PluralOne
+			}
+			return PluralOther
+		},
+	}
+
+	ruleEastSlavic = PluralRule{
+		Categories: []PluralCategory{PluralOne, PluralFew, PluralMany},
+		Select: func(n int) PluralCategory {
+			switch {
+			case n%10 == 1 && n%100 != 11:
+				return PluralOne
+			case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+				return PluralFew
+			default:
+				return PluralMany
+			}
+		},
+	}
+
+	rulePolish = PluralRule{
+		Categories: []PluralCategory{PluralOne, PluralFew, PluralMany},
+		Select: func(n int) PluralCategory {
+			switch {
+			case n == 1:
+				return PluralOne
+			case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+				return PluralFew
+			default:
+				return PluralMany
+			}
+		},
+	}
+
+	ruleCzech = PluralRule{
+		Categories: []PluralCategory{PluralOne, PluralFew, PluralOther},
+		Select: func(n int) PluralCategory {
+			switch {
+			case n == 1:
+				return PluralOne
+			case n >= 2 && n <= 4:
+				return PluralFew
+			default:
+				return PluralOther
+			}
+		},
+	}
+)
+
+// PluralRules maps language codes to plural rules.
+// Only integer values are covered, as the countdown never shows fractions.
+// Add entries to support more languages.
+var PluralRules = map[string]PluralRule{
+	"cs": ruleCzech,
+	"da": ruleOneOther,
+	"de": ruleOneOther,
+	"en": ruleOneOther,
+	"es": ruleOneOther,
+	"fi": ruleOneOther,
+	"fr": ruleZeroOneOther,
+	"it": ruleOneOther,
+	"nb": ruleOneOther,
+	"nl": ruleOneOther,
+	"pl": rulePolish,
+	"pt": ruleZeroOneOther,
+	"ru": ruleEastSlavic,
+	"sk": ruleCzech,
+	"sv": ruleOneOther,
+	"uk": ruleEastSlavic,
+}
+
+// LocaleLabels maps language codes to bundled labels for days, hours, minutes and seconds,
+// in the format accepted by WithLabels.
+var LocaleLabels = map[string]string{
+	"cs": "den|dny|dní,hodina|hodiny|hodin,minuta|minuty|minut,sekunda|sekundy|sekund",
+	"da": "dag|dage,time|timer,minut|minutter,sekund|sekunder",
+	"de": "Tag|Tage,Stunde|Stunden,Minute|Minuten,Sekunde|Sekunden",
+	"en": "day|days,hour|hours,minute|minutes,second|seconds",
+	"es": "día|días,hora|horas,minuto|minutos,segundo|segundos",
+	"fi": "päivä|päivää,tunti|tuntia,minuutti|minuuttia,sekunti|sekuntia",
+	"fr": "jour|jours,heure|heures,minute|minutes,seconde|secondes",
+	"it": "giorno|giorni,ora|ore,minuto|minuti,secondo|secondi",
+	"nb": "dag|dager,time|timer,minutt|minutter,sekund|sekunder",
+	"nl": "dag|dagen,uur|uur,minuut|minuten,seconde|seconden",
+	"pl": "dzień|dni|dni,godzina|godziny|godzin,minuta|minuty|minut,sekunda|sekundy|sekund",
+	"pt": "dia|dias,hora|horas,minuto|minutos,segundo|segundos",
+	"ru": "день|дня|дней,час|часа|часов,минута|минуты|минут,секунда|секунды|секунд",
+	"sk": "deň|dni|dní,hodina|hodiny|hodín,minúta|minúty|minút,sekunda|sekundy|sekúnd",
+	"sv": "dag|dagar,timme|timmar,minut|minuter,sekund|sekunder",
+	"uk": "день|дні|днів,година|години|годин,хвилина|хвилини|хвилин,секунда|секунди|секунд",
+}
+
+// NumberingSystems maps CLDR numbering system names to their zero digit,
+// digits of all listed systems are contiguous code points.
+var NumberingSystems = map[string]rune{
+	"arab":     '\u0660', // Arabic-Indic
+	"arabext":  '\u06F0', // Eastern Arabic, used for Persian and Urdu
+	"beng":     '\u09E6', // Bengali
+	"deva":     '\u0966', // Devanagari
+	"fullwide": '\uFF10', // Full-width
+	"gujr":     '\u0AE6', // Gujarati
+	"guru":     '\u0A66', // Gurmukhi
+	"khmr":     '\u17E0', // Khmer
+	"knda":     '\u0CE6', // Kannada
+	"laoo":     '\u0ED0', // Lao
+	"latn":     '0',      // Latin
+	"mlym":     '\u0D66', // Malayalam
+	"mymr":     '\u1040', // Myanmar
+	"orya":     '\u0B66', // Oriya
+	"tamldec":  '\u0BE6', // Tamil
+	"telu":     '\u0C66', // Telugu
+	"thai":     '\u0E50', // Thai
+	"tibt":     '\u0F20', // Tibetan
+}
+
+// lookupLocale finds value for the locale in m,
+// falling back from regional locale like "pt-BR" to language "pt"
+func lookupLocale[T any](m map[string]T, locale string) (T, bool) {
+	locale = strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+	if v, ok := m[locale]; ok {
+		return v, true
+	}
+
+	lang, _, _ := strings.Cut(locale, "-")
+	v, ok := m[lang]
+	return v, ok
+}
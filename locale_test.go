@@ -0,0 +1,122 @@
+package countdown
+
+import (
+	"testing"
+
+	"golang.org/x/image/font/gofont/gobold"
+)
+
+func TestPluralRules(t *testing.T) {
+	tests := []struct {
+		locale string
+		n      int
+		want   PluralCategory
+	}{
+		{"en", 0, PluralOther},
+		{"en", 1, PluralOne},
+		{"en", 2, PluralOther},
+		{"fr", 0, PluralOne},
+		{"fr", 1, PluralOne},
+		{"fr", 2, PluralOther},
+		{"ru", 1, PluralOne},
+		{"ru", 2, PluralFew},
+		{"ru", 5, PluralMany},
+		{"ru", 11, PluralMany},
+		{"ru", 12, PluralMany},
+		{"ru", 21, PluralOne},
+		{"ru", 22, PluralFew},
+		{"ru", 111, PluralMany},
+		{"pl", 1, PluralOne},
+		{"pl", 21, PluralMany},
+		{"pl", 24, PluralFew},
+		{"cs", 3, PluralFew},
+		{"cs", 5, PluralOther},
+	}
+
+	for _, tt := range tests {
+		rule, ok := lookupLocale(PluralRules, tt.locale)
+		if !ok {
+			t.Fatalf("no plural rule for %q", tt.locale)
+		}
+		if got := rule.Select(tt.n); got != tt.want {
+			t.Errorf("%s: Select(%d) = %v, want %v", tt.locale, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestLabel(t *testing.T) {
+	// the default font has no Cyrillic glyphs
+	g, err := NewGenerator(WithLocale("uk_UA"), WithLabelFontOpenTypeData(gobold.TTF))
+	if err != nil {
+		t.Fatalf("NewGenerator() error = %v", err)
+	}
+
+	tests := []struct {
+		value int
+		want  string
+	}{
+		{1, "хвилина"},
+		{3, "хвилини"},
+		{5, "хвилин"},
+		{14, "хвилин"},
+		{41, "хвилина"},
+	}
+
+	for _, tt := range tests {
+		if got := g.label(segment{unit: UnitMinutes, value: tt.value}); got != tt.want {
+			t.Errorf("label(%d) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestLabelWithoutCategories(t *testing.T) {
+	g := &Generator{
+		Labels:     map[Unit][]string{UnitMinutes: {"min"}},
+		PluralRule: PluralRule{Select: func(n int) PluralCategory { return PluralOther }},
+	}
+
+	if got := g.label(segment{unit: UnitMinutes, value: 5}); got != "min" {
+		t.Errorf("label(5) = %q, want %q", got, "min")
+	}
+}
+
+func TestLocaleLabels(t *testing.T) {
+	for locale, labels := range LocaleLabels {
+		rule, ok := PluralRules[locale]
+		if !ok {
+			t.Errorf("%s: no plural rule", locale)
+			continue
+		}
+
+		g := &Generator{}
+		if err := WithLabels(labels)(g); err != nil {
+			t.Errorf("%s: %v", locale, err)
+			continue
+		}
+		for unit, forms := range g.Labels {
+			if len(forms) != len(rule.Categories) {
+				t.Errorf("%s: unit %d has %d forms, want %d", locale, unit, len(forms), len(rule.Categories))
+			}
+		}
+	}
+}
+
+func TestLocalizeDigits(t *testing.T) {
+	tests := []struct {
+		system string
+		input  string
+		want   string
+	}{
+		{"latn", "01:59", "01:59"},
+		{"arab", "01:59", "٠١:٥٩"},
+		{"arabext", "2024", "۲۰۲۴"},
+		{"deva", "10", "१०"},
+		{"thai", "37", "๓๗"},
+	}
+
+	for _, tt := range tests {
+		if got := localizeDigits(tt.input, NumberingSystems[tt.system]); got != tt.want {
+			t.Errorf("%s: localizeDigits(%q) = %q, want %q", tt.system, tt.input, got, tt.want)
+		}
+	}
+}
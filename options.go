@@ -8,6 +8,9 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"golang.org/x/image/colornames"
@@ -15,7 +18,10 @@ import (
 	"golang.org/x/image/font/opentype"
 )
 
-var ErrTargetTimeInPast = fmt.Errorf("target time is in the past")
+var (
+	ErrTargetTimeInPast  = fmt.Errorf("target time is in the past")
+	ErrStartTimeInFuture = fmt.Errorf("start time is in the future")
+)
 
 type Option func(*Generator) error
 
@@ -46,19 +52,24 @@ func WithFontPath(path string) Option {
 			return nil
 		}
 
-		var err error
-		g.FontFace, err = loadFont(path, g.FontSize)
+		data, err := readFont(path)
 		if err != nil {
 			return fmt.Errorf("failed to load font: %v", err)
 		}
-		return nil
+		return WithFontOpenTypeData(data)(g)
 	}
 }
 
 func WithFontOpenTypeData(data []byte) Option {
 	return func(g *Generator) error {
 		var err error
-		g.FontFace, err = loadOpenTypeFont(data, g.FontSize)
+		g.Font, err = parseFont(data)
+		if err != nil {
+			return fmt.Errorf("failed to load font: %v", err)
+		}
+
+		// the parsed font is kept to create faces of other sizes
+		g.FontFace, err = newFace(g.Font, g.FontSize)
 		if err != nil {
 			return fmt.Errorf("failed to load font: %v", err)
 		}
@@ -66,6 +77,91 @@ func WithFontOpenTypeData(data []byte) Option {
 	}
 }
 
+// WithFallbackFontPath adds a font drawing runes missing in the fonts of the timer,
+// labels, title and subtitle, fallback fonts are tried in the order they are added
+func WithFallbackFontPath(path string) Option {
+	return func(g *Generator) error {
+		if path == "" {
+			return nil
+		}
+
+		data, err := readFont(path)
+		if err != nil {
+			return fmt.Errorf("failed to load fallback font: %v", err)
+		}
+		return WithFallbackFontOpenTypeData(data)(g)
+	}
+}
+
+func WithFallbackFontOpenTypeData(data []byte) Option {
+	return func(g *Generator) error {
+		f, err := parseFont(data)
+		if err != nil {
+			return fmt.Errorf("failed to load fallback font: %v", err)
+		}
+		g.FallbackFonts = append(g.FallbackFonts, f)
+		return nil
+	}
+}
+
+// WithFontAutoFit picks the largest font size the timer fits the frame with,
+// leaving margin in pixels around it, instead of FontSize. It requires an OpenType font.
+func WithFontAutoFit(margin int) Option {
+	return func(g *Generator) error {
+		if margin < 0 {
+			return fmt.Errorf("auto fit margin should not be negative, got %d", margin)
+		}
+		g.FontAutoFit = true
+		g.FontAutoFitMargin = margin
+		return nil
+	}
+}
+
+// WithDigitSpritesPath draws digits and colons with images instead of the font,
+// path is a directory with 0.png to 9.png and colon.png or a sprite sheet, see WithDigitSpritesData
+func WithDigitSpritesPath(path string) Option {
+	return func(g *Generator) error {
+		if path == "" {
+			return nil
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("failed to load digit sprites: %v", err)
+		}
+		if info.IsDir() {
+			g.DigitSprites, err = loadSpriteDir(path)
+			if err != nil {
+				return fmt.Errorf("failed to load digit sprites: %v", err)
+			}
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to load digit sprites: %v", err)
+		}
+		return WithDigitSpritesData(data)(g)
+	}
+}
+
+// WithDigitSpritesData draws digits and colons with images of the sprite sheet,
+// a row of glyphs "0123456789:" in cells of the same width
+func WithDigitSpritesData(data []byte) Option {
+	return func(g *Generator) error {
+		sheet, err := loadImageData(data)
+		if err != nil {
+			return fmt.Errorf("failed to load digit sprites: %v", err)
+		}
+
+		g.DigitSprites, err = splitSpriteSheet(*sheet)
+		if err != nil {
+			return fmt.Errorf("failed to load digit sprites: %v", err)
+		}
+		return nil
+	}
+}
+
 func WithBackgroundColor(c string) Option {
 	return func(g *Generator) error {
 		col, err := parseColor(c)
@@ -77,18 +173,19 @@ func WithBackgroundColor(c string) Option {
 	}
 }
 
+// WithBackgroundImagePath sets the background image,
+// animated GIFs are played under the countdown
 func WithBackgroundImagePath(path string) Option {
 	return func(g *Generator) error {
 		if path == "" {
 			return nil
 		}
 
-		var err error
-		g.BackgroundImage, err = loadImage(path)
+		data, err := os.ReadFile(path)
 		if err != nil {
 			return fmt.Errorf("failed to load image: %v", err)
 		}
-		return nil
+		return WithBackgroundImageData(data)(g)
 	}
 }
 
@@ -99,6 +196,88 @@ func WithBackgroundImageData(data []byte) Option {
 		if err != nil {
 			return fmt.Errorf("failed to load image: %v", err)
 		}
+
+		g.BackgroundAnimation, err = loadAnimation(data)
+		if err != nil {
+			return fmt.Errorf("failed to load animation: %v", err)
+		}
+		return nil
+	}
+}
+
+// WithBackgroundGradient fills the background with a CSS-like gradient
+// instead of the background color, e.g. "linear-gradient(to right, navy, purple)"
+func WithBackgroundGradient(gradient string) Option {
+	return func(g *Generator) error {
+		if gradient == "" {
+			return nil
+		}
+
+		var err error
+		g.BackgroundGradient, err = parseGradient(gradient)
+		if err != nil {
+			return fmt.Errorf("failed to parse gradient: %v", err)
+		}
+		return nil
+	}
+}
+
+// WithTextGradient fills the timer text with a CSS-like gradient
+// spanning the timer instead of the text color, e.g. "linear-gradient(gold, orange)"
+func WithTextGradient(gradient string) Option {
+	return func(g *Generator) error {
+		if gradient == "" {
+			return nil
+		}
+
+		var err error
+		g.TextGradient, err = parseGradient(gradient)
+		if err != nil {
+			return fmt.Errorf("failed to parse gradient: %v", err)
+		}
+		return nil
+	}
+}
+
+// WithBackgroundFit sets how the background image fits into the frame:
+// "none", "cover", "contain", "stretch", "tile" or "center"
+func WithBackgroundFit(fit string) Option {
+	return func(g *Generator) error {
+		if fit == "" {
+			return nil
+		}
+
+		var err error
+		g.BackgroundFit, err = parseFit(fit)
+		if err != nil {
+			return fmt.Errorf("failed to parse background fit: %v", err)
+		}
+		return nil
+	}
+}
+
+// WithBackgroundAnchor aligns the fitted background image in the frame,
+// e.g. "center", "top-left" or "bottom"
+func WithBackgroundAnchor(anchor string) Option {
+	return func(g *Generator) error {
+		if anchor == "" {
+			return nil
+		}
+
+		var err error
+		g.BackgroundAnchor, err = parseAnchor(anchor)
+		if err != nil {
+			return fmt.Errorf("failed to parse background anchor: %v", err)
+		}
+		return nil
+	}
+}
+
+// WithBackgroundOffset moves the fitted background image from its anchor in pixels
+func WithBackgroundOffset(x, y int) Option {
+	return func(g *Generator) error {
+		g.BackgroundOffsetX = x
+		g.BackgroundOffsetY = y
 		return nil
 	}
 }
@@ -121,110 +300,1101 @@ func WithTimeFrom(d time.Duration) Option {
 	}
 }
 
+// WithTargetTime sets the time to count down to in Unix format.
+// NewGenerator returns ErrTargetTimeInPast if the target time has passed,
+// unless the expired frame is configured.
 func WithTargetTime(t int) Option {
 	return func(g *Generator) error {
 		if t == 0 {
 			return nil
 		}
 		g.TimeFrom = time.Until(time.Unix(int64(t), 0))
+		return nil
+	}
+}
+
+// WithExpiredText sets text drawn instead of the timer
+// once the countdown reaches zero, e.g. "Sale ended".
+func WithExpiredText(text string) Option {
+	return func(g *Generator) error {
+		g.ExpiredText = text
+		return nil
+	}
+}
+
+// WithExpiredBackgroundImagePath sets background image
+// used once the countdown reaches zero.
+func WithExpiredBackgroundImagePath(path string) Option {
+	return func(g *Generator) error {
+		if path == "" {
+			return nil
+		}
+
+		var err error
+		g.ExpiredBackgroundImage, err = loadImage(path)
+		if err != nil {
+			return fmt.Errorf("failed to load image: %v", err)
+		}
+		return nil
+	}
+}
+
+func WithExpiredBackgroundImageData(data []byte) Option {
+	return func(g *Generator) error {
+		var err error
+		g.ExpiredBackgroundImage, err = loadImageData(data)
+		if err != nil {
+			return fmt.Errorf("failed to load image: %v", err)
+		}
+		return nil
+	}
+}
+
+// WithExpiredHold keeps showing zeros when the target time has passed,
+// instead of failing with ErrTargetTimeInPast.
+func WithExpiredHold() Option {
+	return func(g *Generator) error {
+		g.ExpiredHold = true
+		return nil
+	}
+}
+
+// WithCountUp makes the timer count elapsed time upwards from TimeFrom,
+// e.g. for "time since" or "uptime" badges.
+// If MaxFrames is not set, frames are generated for 60 seconds.
+func WithCountUp() Option {
+	return func(g *Generator) error {
+		g.CountUp = true
+		return nil
+	}
+}
+
+// WithStartTime enables count up mode starting from the time elapsed
+// since the start time in Unix format.
+func WithStartTime(t int) Option {
+	return func(g *Generator) error {
+		if t == 0 {
+			return nil
+		}
+		g.CountUp = true
+		g.TimeFrom = time.Since(time.Unix(int64(t), 0))
 		if g.TimeFrom < 0 {
-			return ErrTargetTimeInPast
+			return ErrStartTimeInFuture
 		}
 		return nil
 	}
 }
 
-func WithMaxFrames(max int) Option {
+// WithFPS sets the number of frames per second, from 1 to 50.
+// GIF delays are set in 100ths of a second, so for frame rates
+// that don't divide 100 delays alternate to keep each second exact.
+func WithFPS(fps int) Option {
 	return func(g *Generator) error {
-		g.MaxFrames = max
+		if fps == 0 {
+			return nil
+		}
+		if fps < 0 || fps > 50 {
+			return fmt.Errorf("fps should be between 1 and 50, got %d", fps)
+		}
+		g.FPS = fps
 		return nil
 	}
 }
 
-func WithColonCompensation(y int) Option {
+// WithFractionDigits adds fraction of a second to the timer in the last minute:
+// 1 for tenths, 2 for hundredths. Use it with WithFPS to animate the fraction.
+func WithFractionDigits(n int) Option {
 	return func(g *Generator) error {
-		g.ColonCompensation = y
+		if n < 0 || n > 2 {
+			return fmt.Errorf("fraction digits should be between 0 and 2, got %d", n)
+		}
+		g.FractionDigits = n
 		return nil
 	}
 }
 
-func WithColonCompensationAuto() Option {
+// WithFlipClock draws each digit on its own rounded card
+// and animates changing digits like a split-flap display,
+// unless another transition is set with WithTransition.
+func WithFlipClock() Option {
 	return func(g *Generator) error {
-		g.ColonCompoensationAuto = true
+		g.FlipClock = true
 		return nil
 	}
 }
 
-func WithPaletteMaxColors(n int) Option {
+func WithCardColor(c string) Option {
+	return func(g *Generator) error {
+		col, err := parseColor(c)
+		if err != nil {
+			return fmt.Errorf("failed to parse color: %v", err)
+		}
+		g.CardColor = col
+		return nil
+	}
+}
+
+func WithCardRadius(radius int) Option {
+	return func(g *Generator) error {
+		g.CardRadius = radius
+		return nil
+	}
+}
+
+// WithCardPadding sets space in pixels between the digit and the card edge,
+// cards of the same part are separated by half of the padding
+func WithCardPadding(padding int) Option {
+	return func(g *Generator) error {
+		g.CardPadding = padding
+		return nil
+	}
+}
+
+// WithTransitionFrames sets the number of intermediate frames
+// inserted to animate changing digits
+func WithTransitionFrames(n int) Option {
 	return func(g *Generator) error {
 		if n <= 0 {
 			return nil
 		}
-		g.PaletteMaxColors = n
+		g.TransitionFrames = n
 		return nil
 	}
 }
 
-func WithPalleteMaxColorsAuto() Option {
+// WithTransition sets the animation of changing digits:
+// "none", "flip", "slide", "fade" or "roll"
+func WithTransition(name string) Option {
 	return func(g *Generator) error {
-		g.PaletteMaxColorsAuto = true
+		if name == "" {
+			return nil
+		}
+
+		var err error
+		g.Transition, err = parseTransition(name)
+		if err != nil {
+			return fmt.Errorf("failed to parse transition: %v", err)
+		}
 		return nil
 	}
 }
 
-func WithoutLeadingZeros() Option {
+// WithTransitionDuration sets the fraction of a frame
+// taken by the transition, e.g. 0.5 for the first half of a second
+func WithTransitionDuration(fraction float64) Option {
 	return func(g *Generator) error {
-		g.NoLeadingZeros = true
+		if fraction == 0 {
+			return nil
+		}
+		if fraction < 0 || fraction > 1 {
+			return fmt.Errorf("transition duration should be between 0 and 1, got %v", fraction)
+		}
+		g.TransitionDuration = fraction
 		return nil
 	}
 }
 
-func loadFont(path string, size float64) (font.Face, error) {
-	f, err := os.Open(path)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %v", err)
+// WithBlinkingSeparator hides separators between fields
+// in the second half of every second, like digital clocks do
+func WithBlinkingSeparator() Option {
+	return func(g *Generator) error {
+		g.BlinkSeparator = true
+		return nil
 	}
-	defer f.Close()
+}
 
-	fontData, err := io.ReadAll(f)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read file: %v", err)
-	}
+// WithSeparatorDimColor sets the color of blinking separators
+// in the second half of a second, instead of hiding them
+func WithSeparatorDimColor(c string) Option {
+	return func(g *Generator) error {
+		if c == "" {
+			return nil
+		}
 
-	// switch between opentype and truetype based on file extension
-	switch ext := filepath.Ext(path); ext {
-	case ".otf", ".ttf":
-		return loadOpenTypeFont(fontData, size)
-	default:
-		return nil, fmt.Errorf("unsupported font format: %s", ext)
+		col, err := parseColor(c)
+		if err != nil {
+			return fmt.Errorf("failed to parse color: %v", err)
+		}
+		g.SeparatorDimColor = col
+		return nil
 	}
 }
 
-func loadOpenTypeFont(data []byte, size float64) (font.Face, error) {
-	otFontFace, err := opentype.Parse(data)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse font: %v", err)
+// WithSeparators replaces colons of the default format with the text
+// after days, hours and minutes, e.g. "d ,h ,m".
+// A single value is used for all of them, empty values keep the colon.
+func WithSeparators(list string) Option {
+	return func(g *Generator) error {
+		if list == "" {
+			return nil
+		}
+
+		return g.updateSeparators(list, func(s *Separator, value string) error {
+			s.Text = value
+			return nil
+		})
 	}
+}
 
-	return opentype.NewFace(otFontFace, &opentype.FaceOptions{
-		Size:    size,
-		DPI:     72,
-		Hinting: font.HintingFull,
-	})
+// WithSeparatorOffsets sets vertical offsets of separators
+// after days, hours and minutes in pixels, e.g. "4" or "0,4,4",
+// replacing ColonCompensation for them
+func WithSeparatorOffsets(list string) Option {
+	return func(g *Generator) error {
+		if list == "" {
+			return nil
+		}
+
+		return g.updateSeparators(list, func(s *Separator, value string) error {
+			offset, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return fmt.Errorf("failed to parse separator offset: %v", err)
+			}
+			s.Offset = &offset
+			return nil
+		})
+	}
 }
 
-func parseColor(colorNameOrCode string) (color.Color, error) {
-	if color, ok := colornames.Map[colorNameOrCode]; ok {
-		return color, nil
+// WithSeparatorImagePath draws the image instead of every separator
+func WithSeparatorImagePath(path string) Option {
+	return func(g *Generator) error {
+		if path == "" {
+			return nil
+		}
+
+		img, err := loadImage(path)
+		if err != nil {
+			return fmt.Errorf("failed to load image: %v", err)
+		}
+		return g.updateSeparators("", func(s *Separator, _ string) error {
+			s.Image = *img
+			return nil
+		})
 	}
+}
 
-	return parseHexColor(colorNameOrCode)
+func WithSeparatorImageData(data []byte) Option {
+	return func(g *Generator) error {
+		img, err := loadImageData(data)
+		if err != nil {
+			return fmt.Errorf("failed to load image: %v", err)
+		}
+		return g.updateSeparators("", func(s *Separator, _ string) error {
+			s.Image = *img
+			return nil
+		})
+	}
+}
+
+// WithColorRules sets comma-separated text color rules, e.g. "s:orange,5m:red".
+// Rule applies to fields of units ("s", "hm", "f" for fractions),
+// to the whole timer below the duration ("5m"), or both ("s@10s").
+// The last matching rule wins.
+func WithColorRules(rules string) Option {
+	return func(g *Generator) error {
+		if rules == "" {
+			return nil
+		}
+
+		var err error
+		g.ColorRules, err = parseColorRules(rules)
+		if err != nil {
+			return fmt.Errorf("failed to parse color rules: %v", err)
+		}
+		return nil
+	}
+}
+
+// WithStrokeWidth outlines the text with the stroke of the width in pixels
+func WithStrokeWidth(width int) Option {
+	return func(g *Generator) error {
+		if width < 0 {
+			return fmt.Errorf("stroke width should not be negative, got %d", width)
+		}
+		g.StrokeWidth = width
+		return nil
+	}
+}
+
+func WithStrokeColor(c string) Option {
+	return func(g *Generator) error {
+		if c == "" {
+			return nil
+		}
+
+		col, err := parseColor(c)
+		if err != nil {
+			return fmt.Errorf("failed to parse color: %v", err)
+		}
+		g.StrokeColor = col
+		return nil
+	}
+}
+
+// WithShadowColor adds a drop shadow of the color behind the text
+func WithShadowColor(c string) Option {
+	return func(g *Generator) error {
+		if c == "" {
+			return nil
+		}
+
+		col, err := parseColor(c)
+		if err != nil {
+			return fmt.Errorf("failed to parse color: %v", err)
+		}
+		g.ShadowColor = col
+		return nil
+	}
+}
+
+// WithShadowOffset sets the shadow offset in pixels, positive values move it right and down
+func WithShadowOffset(x, y int) Option {
+	return func(g *Generator) error {
+		g.ShadowOffsetX = x
+		g.ShadowOffsetY = y
+		return nil
+	}
+}
+
+// WithShadowBlur sets the shadow blur radius in pixels, 0 for a sharp shadow
+func WithShadowBlur(radius int) Option {
+	return func(g *Generator) error {
+		if radius < 0 {
+			return fmt.Errorf("shadow blur should not be negative, got %d", radius)
+		}
+		g.ShadowBlur = radius
+		return nil
+	}
+}
+
+// WithTimerAnchor aligns the timer with labels in the frame,
+// e.g. "center", "top-left" or "bottom"
+func WithTimerAnchor(anchor string) Option {
+	return func(g *Generator) error {
+		if anchor == "" {
+			return nil
+		}
+
+		var err error
+		g.TimerAnchor, err = parseAnchor(anchor)
+		if err != nil {
+			return fmt.Errorf("failed to parse timer anchor: %v", err)
+		}
+		return nil
+	}
+}
+
+// WithTimerPadding sets space in pixels between the frame edges and the anchored timer
+func WithTimerPadding(padding int) Option {
+	return func(g *Generator) error {
+		if padding < 0 {
+			return fmt.Errorf("timer padding should not be negative, got %d", padding)
+		}
+		g.TimerPadding = padding
+		return nil
+	}
+}
+
+// WithTimerPosition places the anchor point of the timer at x, y,
+// e.g. with "top-left" anchor it is the top left corner of the timer
+func WithTimerPosition(x, y int) Option {
+	return func(g *Generator) error {
+		g.TimerPosition = &image.Point{X: x, Y: y}
+		return nil
+	}
+}
+
+// WithTitle adds static text above the timer, wrapped into lines to fit the width
+func WithTitle(text string) Option {
+	return func(g *Generator) error {
+		g.Title.Text = text
+		return nil
+	}
+}
+
+func WithTitleFontSize(size float64) Option {
+	return func(g *Generator) error {
+		g.Title.FontSize = size
+		return nil
+	}
+}
+
+func WithTitleFontPath(path string) Option {
+	return func(g *Generator) error {
+		return setBlockFontPath(&g.Title, "title", path)
+	}
+}
+
+func WithTitleFontOpenTypeData(data []byte) Option {
+	return func(g *Generator) error {
+		return setBlockFontData(&g.Title, "title", data)
+	}
+}
+
+// WithTitleColor sets title color, by default it uses text color
+func WithTitleColor(c string) Option {
+	return func(g *Generator) error {
+		return setBlockColor(&g.Title, "title", c)
+	}
+}
+
+// WithTitleWidth sets the width in pixels the title is wrapped at,
+// by default it is the width of the frame without padding
+func WithTitleWidth(width int) Option {
+	return func(g *Generator) error {
+		if width < 0 {
+			return fmt.Errorf("title width should not be negative, got %d", width)
+		}
+		g.Title.Width = width
+		return nil
+	}
+}
+
+// WithSubtitle adds static text below the timer, wrapped into lines to fit the width
+func WithSubtitle(text string) Option {
+	return func(g *Generator) error {
+		g.Subtitle.Text = text
+		return nil
+	}
+}
+
+func WithSubtitleFontSize(size float64) Option {
+	return func(g *Generator) error {
+		g.Subtitle.FontSize = size
+		return nil
+	}
+}
+
+func WithSubtitleFontPath(path string) Option {
+	return func(g *Generator) error {
+		return setBlockFontPath(&g.Subtitle, "subtitle", path)
+	}
+}
+
+func WithSubtitleFontOpenTypeData(data []byte) Option {
+	return func(g *Generator) error {
+		return setBlockFontData(&g.Subtitle, "subtitle", data)
+	}
+}
+
+// WithSubtitleColor sets subtitle color, by default it uses text color
+func WithSubtitleColor(c string) Option {
+	return func(g *Generator) error {
+		return setBlockColor(&g.Subtitle, "subtitle", c)
+	}
+}
+
+// WithSubtitleWidth sets the width in pixels the subtitle is wrapped at,
+// by default it is the width of the frame without padding
+func WithSubtitleWidth(width int) Option {
+	return func(g *Generator) error {
+		if width < 0 {
+			return fmt.Errorf("subtitle width should not be negative, got %d", width)
+		}
+		g.Subtitle.Width = width
+		return nil
+	}
+}
+
+// WithLayers adds layers drawn over the background in the given order,
+// include CountdownLayer to draw the timer, e.g. between a logo and a badge
+func WithLayers(layers ...Layer) Option {
+	return func(g *Generator) error {
+		g.Layers = append(g.Layers, layers...)
+		return nil
+	}
+}
+
+// WithProgressBar shows the time left as a horizontal bar aligned to the anchor,
+// "bottom" if empty
+func WithProgressBar(anchor string) Option {
+	return func(g *Generator) error {
+		return g.setProgress(ProgressBar, anchor, AnchorBottom)
+	}
+}
+
+// WithProgressRing shows the time left as a ring aligned to the anchor,
+// "center" if empty
+func WithProgressRing(anchor string) Option {
+	return func(g *Generator) error {
+		return g.setProgress(ProgressRing, anchor, AnchorCenter)
+	}
+}
+
+// WithProgressColor sets the color of the progress, by default it uses text color
+func WithProgressColor(c string) Option {
+	return func(g *Generator) error {
+		if c == "" {
+			return nil
+		}
+
+		col, err := parseColor(c)
+		if err != nil {
+			return fmt.Errorf("failed to parse progress color: %v", err)
+		}
+		g.Progress.Color = col
+		return nil
+	}
+}
+
+// WithProgressTrackColor sets the color of the depleted part of the progress
+func WithProgressTrackColor(c string) Option {
+	return func(g *Generator) error {
+		if c == "" {
+			return nil
+		}
+
+		col, err := parseColor(c)
+		if err != nil {
+			return fmt.Errorf("failed to parse progress track color: %v", err)
+		}
+		g.Progress.TrackColor = col
+		return nil
+	}
+}
+
+func WithProgressThickness(thickness int) Option {
+	return func(g *Generator) error {
+		if thickness < 1 {
+			return fmt.Errorf("progress thickness should be positive, got %d", thickness)
+		}
+		g.Progress.Thickness = thickness
+		return nil
+	}
+}
+
+// WithProgressSize sets the length of the bar or the diameter of the ring in pixels,
+// by default they fill the frame
+func WithProgressSize(size int) Option {
+	return func(g *Generator) error {
+		if size < 0 {
+			return fmt.Errorf("progress size should not be negative, got %d", size)
+		}
+		g.Progress.Size = size
+		return nil
+	}
+}
+
+// WithProgressOffset moves the progress from its anchor
+func WithProgressOffset(x, y int) Option {
+	return func(g *Generator) error {
+		g.Progress.Offset = image.Pt(x, y)
+		return nil
+	}
+}
+
+func WithMaxFrames(max int) Option {
+	return func(g *Generator) error {
+		g.MaxFrames = max
+		return nil
+	}
+}
+
+func WithColonCompensation(y int) Option {
+	return func(g *Generator) error {
+		g.ColonCompensation = y
+		return nil
+	}
+}
+
+func WithColonCompensationAuto() Option {
+	return func(g *Generator) error {
+		g.ColonCompoensationAuto = true
+		return nil
+	}
+}
+
+func WithPaletteMaxColors(n int) Option {
+	return func(g *Generator) error {
+		if n <= 0 {
+			return nil
+		}
+		g.PaletteMaxColors = n
+		return nil
+	}
+}
+
+func WithPalleteMaxColorsAuto() Option {
+	return func(g *Generator) error {
+		g.PaletteMaxColorsAuto = true
+		return nil
+	}
+}
+
+func WithoutLeadingZeros() Option {
+	return func(g *Generator) error {
+		g.NoLeadingZeros = true
+		return nil
+	}
+}
+
+// WithUnits sets units to show, e.g. "dhms", "dh" or "ms".
+// The largest unit absorbs the overflow, so "ms" shows 90 minutes as 90:00.
+// By default units are picked automatically based on the remaining time.
+func WithUnits(units string) Option {
+	return func(g *Generator) error {
+		var err error
+		g.Units, err = parseUnits(units)
+		if err != nil {
+			return fmt.Errorf("failed to parse units: %v", err)
+		}
+		return nil
+	}
+}
+
+// WithFormat sets template of the countdown text, e.g. "{d}d {hh}h {mm}m" or "T-{h}:{mm}:{ss}".
+// Fields {d}, {h}, {m} and {s} are replaced with days, hours, minutes and seconds,
+// repeated letters pad the value with zeros. Literal text between fields
+// is treated as separator and moved by the colon compensation.
+func WithFormat(format string) Option {
+	return func(g *Generator) error {
+		if format == "" {
+			return nil
+		}
+
+		if _, err := parseFormat(format); err != nil {
+			return fmt.Errorf("failed to parse format: %v", err)
+		}
+		g.Format = format
+		return nil
+	}
+}
+
+// WithLabels sets captions drawn under each part of the timer,
+// as comma-separated list for days, hours, minutes and seconds,
+// e.g. "DAYS,HOURS,MINUTES,SECONDS".
+// Each label may list plural forms separated by "|" in the order
+// of the locale plural categories, e.g. "day|days" or "день|дня|дней".
+func WithLabels(labels string) Option {
+	return func(g *Generator) error {
+		if labels == "" {
+			return nil
+		}
+
+		list := strings.Split(labels, ",")
+		if len(list) != len(unitDurations) {
+			return fmt.Errorf("expected %d labels, got %d", len(unitDurations), len(list))
+		}
+
+		g.Labels = make(map[Unit][]string, len(list))
+		for i, label := range list {
+			forms := strings.Split(strings.TrimSpace(label), "|")
+			if len(forms) == 1 && forms[0] == "" {
+				continue
+			}
+			g.Labels[unitDurations[i].unit] = forms
+		}
+		return nil
+	}
+}
+
+// WithLabelAlign sets labels alignment under the timer parts:
+// "start", "center" or "end" of the part in the reading direction
+func WithLabelAlign(align string) Option {
+	return func(g *Generator) error {
+		if align == "" {
+			return nil
+		}
+
+		var err error
+		g.LabelAlign, err = parseAlign(align)
+		if err != nil {
+			return fmt.Errorf("failed to parse label align: %v", err)
+		}
+		return nil
+	}
+}
+
+// WithLocale sets the plural rule used to pick label forms.
+// If labels are not set, bundled labels for the locale are used.
+func WithLocale(locale string) Option {
+	return func(g *Generator) error {
+		if locale == "" {
+			return nil
+		}
+
+		rule, ok := lookupLocale(PluralRules, locale)
+		if !ok {
+			return fmt.Errorf("unsupported locale: %s", locale)
+		}
+		g.PluralRule = rule
+		g.Locale = locale
+		return nil
+	}
+}
+
+func WithLabelFontSize(size float64) Option {
+	return func(g *Generator) error {
+		g.LabelFontSize = size
+		return nil
+	}
+}
+
+func WithLabelFontPath(path string) Option {
+	return func(g *Generator) error {
+		if path == "" {
+			return nil
+		}
+
+		var err error
+		g.LabelFontFace, err = loadFont(path, g.LabelFontSize)
+		if err != nil {
+			return fmt.Errorf("failed to load label font: %v", err)
+		}
+		return nil
+	}
+}
+
+func WithLabelFontOpenTypeData(data []byte) Option {
+	return func(g *Generator) error {
+		var err error
+		g.LabelFontFace, err = loadOpenTypeFont(data, g.LabelFontSize)
+		if err != nil {
+			return fmt.Errorf("failed to load label font: %v", err)
+		}
+		return nil
+	}
+}
+
+// WithLabelColor sets labels color, by default labels use text color
+func WithLabelColor(c string) Option {
+	return func(g *Generator) error {
+		if c == "" {
+			return nil
+		}
+
+		col, err := parseColor(c)
+		if err != nil {
+			return fmt.Errorf("failed to parse color: %v", err)
+		}
+		g.LabelColor = col
+		return nil
+	}
+}
+
+// WithLabelSpacing sets the gap in pixels between the timer and labels
+func WithLabelSpacing(spacing int) Option {
+	return func(g *Generator) error {
+		g.LabelSpacing = spacing
+		return nil
+	}
+}
+
+// WithNumberingSystem sets digits used by the timer,
+// e.g. "arab" for Arabic-Indic or "deva" for Devanagari, see NumberingSystems.
+func WithNumberingSystem(name string) Option {
+	return func(g *Generator) error {
+		if name == "" {
+			return nil
+		}
+
+		zero, ok := NumberingSystems[strings.ToLower(name)]
+		if !ok {
+			return fmt.Errorf("unsupported numbering system: %s", name)
+		}
+		g.ZeroDigit = zero
+		return nil
+	}
+}
+
+// WithRTL enables right-to-left layout: parts of the timer go from right to left
+// (e.g. SS:MM:HH) and labels alignment is mirrored, digits keep their order.
+func WithRTL() Option {
+	return func(g *Generator) error {
+		g.RTL = true
+		return nil
+	}
+}
+
+func setBlockFontPath(b *TextBlock, name, path string) error {
+	if path == "" {
+		return nil
+	}
+
+	var err error
+	b.FontFace, err = loadFont(path, b.FontSize)
+	if err != nil {
+		return fmt.Errorf("failed to load %s font: %v", name, err)
+	}
+	return nil
+}
+
+func setBlockFontData(b *TextBlock, name string, data []byte) error {
+	var err error
+	b.FontFace, err = loadOpenTypeFont(data, b.FontSize)
+	if err != nil {
+		return fmt.Errorf("failed to load %s font: %v", name, err)
+	}
+	return nil
+}
+
+func setBlockColor(b *TextBlock, name, c string) error {
+	if c == "" {
+		return nil
+	}
+
+	col, err := parseColor(c)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s color: %v", name, err)
+	}
+	b.Color = col
+	return nil
+}
+
+func loadFont(path string, size float64) (font.Face, error) {
+	data, err := readFont(path)
+	if err != nil {
+		return nil, err
+	}
+	return loadOpenTypeFont(data, size)
+}
+
+// readFont reads the font file, checking its format
+func readFont(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %v", err)
+	}
+	defer f.Close()
+
+	fontData, err := io.ReadAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %v", err)
+	}
+
+	// switch between opentype and truetype based on file extension
+	switch ext := filepath.Ext(path); ext {
+	case ".otf", ".ttf":
+		return fontData, nil
+	default:
+		return nil, fmt.Errorf("unsupported font format: %s", ext)
+	}
+}
+
+func loadOpenTypeFont(data []byte, size float64) (font.Face, error) {
+	otFont, err := parseFont(data)
+	if err != nil {
+		return nil, err
+	}
+	return newFace(otFont, size)
+}
+
+func parseFont(data []byte) (*opentype.Font, error) {
+	otFont, err := opentype.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse font: %v", err)
+	}
+	return otFont, nil
+}
+
+func newFace(f *opentype.Font, size float64) (font.Face, error) {
+	return opentype.NewFace(f, &opentype.FaceOptions{
+		Size:    size,
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
+}
+
+func parseColor(colorNameOrCode string) (color.Color, error) {
+	if color, ok := colornames.Map[colorNameOrCode]; ok {
+		return color, nil
+	}
+
+	return parseHexColor(colorNameOrCode)
+}
+
+func parseUnits(s string) (Unit, error) {
+	var units Unit
+	for _, r := range s {
+		switch r {
+		case 'd':
+			units |= UnitDays
+		case 'h':
+			units |= UnitHours
+		case 'm':
+			units |= UnitMinutes
+		case 's':
+			units |= UnitSeconds
+		default:
+			return 0, fmt.Errorf("unknown unit %q", r)
+		}
+	}
+	return units, nil
+}
+
+func parseColorRules(s string) ([]ColorRule, error) {
+	var rules []ColorRule
+	for _, item := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(item), ":")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("expected rule:color, got %q", item)
+		}
+
+		var (
+			rule ColorRule
+			err  error
+		)
+
+		units, below, hasBelow := strings.Cut(key, "@")
+		if !hasBelow && key != "" && key[0] >= '0' && key[0] <= '9' {
+			units, below, hasBelow = "", key, true
+		}
+
+		for _, r := range units {
+			if r == 'f' {
+				rule.Units |= UnitFraction
+				continue
+			}
+			u, err := parseUnits(string(r))
+			if err != nil {
+				return nil, err
+			}
+			rule.Units |= u
+		}
+
+		if hasBelow {
+			rule.Below, err = time.ParseDuration(below)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse duration: %v", err)
+			}
+		}
+
+		if rule.Units == 0 && rule.Below == 0 {
+			return nil, fmt.Errorf("rule %q has no units or duration", item)
+		}
+
+		rule.Color, err = parseColor(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse color: %v", err)
+		}
+
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func parseAlign(s string) (Align, error) {
+	switch s {
+	case "start":
+		return AlignStart, nil
+	case "center":
+		return AlignCenter, nil
+	case "end":
+		return AlignEnd, nil
+	default:
+		return 0, fmt.Errorf("unknown align %q", s)
+	}
+}
+
+// setProgress enables the progress of the style aligned to the anchor or to the default one
+func (g *Generator) setProgress(style ProgressStyle, anchor string, defaultAnchor Anchor) error {
+	g.Progress.Style = style
+	g.Progress.Anchor = defaultAnchor
+	if anchor == "" {
+		return nil
+	}
+
+	var err error
+	g.Progress.Anchor, err = parseAnchor(anchor)
+	if err != nil {
+		return fmt.Errorf("failed to parse progress anchor: %v", err)
+	}
+	return nil
+}
+
+// separatorUnits are units followed by a separator in the default format
+var separatorUnits = []Unit{UnitDays, UnitHours, UnitMinutes}
+
+// updateSeparators calls update for separators after days, hours and minutes
+// with values of the comma-separated list, a single value is used for all of them
+func (g *Generator) updateSeparators(list string, update func(s *Separator, value string) error) error {
+	values := strings.Split(list, ",")
+	if len(values) == 1 {
+		values = slices.Repeat(values, len(separatorUnits))
+	}
+	if len(values) != len(separatorUnits) {
+		return fmt.Errorf("expected 1 or %d separators, got %d", len(separatorUnits), len(values))
+	}
+
+	if g.Separators == nil {
+		g.Separators = make(map[Unit]Separator, len(separatorUnits))
+	}
+	for i, u := range separatorUnits {
+		s := g.Separators[u]
+		if err := update(&s, values[i]); err != nil {
+			return err
+		}
+		g.Separators[u] = s
+	}
+	return nil
+}
+
+func parseFit(s string) (Fit, error) {
+	switch s {
+	case "none":
+		return FitNone, nil
+	case "cover":
+		return FitCover, nil
+	case "contain":
+		return FitContain, nil
+	case "stretch":
+		return FitStretch, nil
+	case "tile":
+		return FitTile, nil
+	case "center":
+		return FitCenter, nil
+	default:
+		return 0, fmt.Errorf("unknown fit %q", s)
+	}
+}
+
+func parseAnchor(s string) (Anchor, error) {
+	switch s {
+	case "center":
+		return AnchorCenter, nil
+	case "top-left":
+		return AnchorTopLeft, nil
+	case "top":
+		return AnchorTop, nil
+	case "top-right":
+		return AnchorTopRight, nil
+	case "left":
+		return AnchorLeft, nil
+	case "right":
+		return AnchorRight, nil
+	case "bottom-left":
+		return AnchorBottomLeft, nil
+	case "bottom":
+		return AnchorBottom, nil
+	case "bottom-right":
+		return AnchorBottomRight, nil
+	default:
+		return 0, fmt.Errorf("unknown anchor %q", s)
+	}
+}
+
+func parseTransition(s string) (Transition, error) {
+	switch s {
+	case "none":
+		return TransitionNone, nil
+	case "flip":
+		return TransitionFlip, nil
+	case "slide":
+		return TransitionSlide, nil
+	case "fade":
+		return TransitionFade, nil
+	case "roll":
+		return TransitionRoll, nil
+	default:
+		return 0, fmt.Errorf("unknown transition %q", s)
+	}
 }
 
 var errInvalidColorHexFormat = fmt.Errorf("invalid color format")
 
 func parseHexColor(hex string) (c color.RGBA, err error) {
-	if hex[0] != '#' {
+	if hex == "" || hex[0] != '#' {
 		return c, errInvalidColorHexFormat
 	}
 
@@ -0,0 +1,128 @@
+package countdown
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"golang.org/x/image/vector"
+)
+
+// ProgressStyle is a shape of the progress widget.
+type ProgressStyle int
+
+const (
+	ProgressNone ProgressStyle = iota
+	// ProgressBar is a horizontal bar depleting towards the start of the line
+	ProgressBar
+	// ProgressRing is a ring depleting counterclockwise from the top
+	ProgressRing
+)
+
+// Progress shows the time left of the countdown, see FrameState.Progress.
+// It is a layer drawn under the timer, unless Generator.Layers is set.
+type Progress struct {
+	Style      ProgressStyle
+	Color      color.Color // text color if nil
+	TrackColor color.Color // color of the depleted part, not drawn if nil
+	Thickness  int
+	Size       int // length of the bar or diameter of the ring, zero to fill the frame
+	Anchor     Anchor
+	Offset     image.Point
+}
+
+func (p *Progress) Draw(dst draw.Image, state FrameState) {
+	if p.Style == ProgressNone {
+		return
+	}
+
+	c := p.Color
+	if c == nil && state.g != nil {
+		c = state.g.TextColor
+	}
+	if c == nil {
+		c = color.White
+	}
+	rtl := state.g != nil && state.g.RTL
+
+	b := dst.Bounds()
+	fill := func(c color.Color, path func(z *vector.Rasterizer)) {
+		z := vector.NewRasterizer(b.Dx(), b.Dy())
+		path(z)
+		mask := image.NewAlpha(b)
+		z.Draw(mask, b, image.Opaque, image.Point{})
+		draw.DrawMask(dst, b, image.NewUniform(c), image.Point{}, mask, b.Min, draw.Over)
+	}
+
+	switch p.Style {
+	case ProgressBar:
+		length := p.Size
+		if length == 0 {
+			length = b.Dx()
+		}
+		at := p.Anchor.position(b, image.Pt(length, p.Thickness)).Add(p.Offset).Sub(b.Min)
+		x, y := float32(at.X), float32(at.Y)
+		w, h := float32(length), float32(p.Thickness)
+
+		// the filled part keeps to the start of the line
+		filled := w * float32(state.Progress)
+		start, end := x, x+filled
+		if rtl {
+			start, end = x+w-filled, x+w
+		}
+
+		if p.TrackColor != nil {
+			fill(p.TrackColor, func(z *vector.Rasterizer) { rect(z, x, y, x+w, y+h) })
+		}
+		if filled > 0 {
+			fill(c, func(z *vector.Rasterizer) { rect(z, start, y, end, y+h) })
+		}
+
+	case ProgressRing:
+		d := p.Size
+		if d == 0 {
+			d = min(b.Dx(), b.Dy())
+		}
+		at := p.Anchor.position(b, image.Pt(d, d)).Add(p.Offset).Sub(b.Min)
+		r := float64(d) / 2
+		cx, cy := float64(at.X)+r, float64(at.Y)+r
+		inner := max(r-float64(p.Thickness), 0)
+
+		if p.TrackColor != nil {
+			fill(p.TrackColor, func(z *vector.Rasterizer) { ring(z, cx, cy, r, inner, 0, 2*math.Pi) })
+		}
+		if sweep := 2 * math.Pi * state.Progress; sweep > 0 {
+			fill(c, func(z *vector.Rasterizer) { ring(z, cx, cy, r, inner, 0, sweep) })
+		}
+	}
+}
+
+// rect adds the rectangle to the path
+func rect(z *vector.Rasterizer, x0, y0, x1, y1 float32) {
+	z.MoveTo(x0, y0)
+	z.LineTo(x1, y0)
+	z.LineTo(x1, y1)
+	z.LineTo(x0, y1)
+	z.ClosePath()
+}
+
+// ring adds the sector of the ring around cx, cy between radii r and inner to the path,
+// angles are clockwise from the top in radians
+func ring(z *vector.Rasterizer, cx, cy, r, inner, a0, a1 float64) {
+	// arcs are approximated with segments of 2 degrees
+	n := max(1, int(math.Ceil((a1-a0)/(math.Pi/90))))
+	point := func(radius, a float64) (float32, float32) {
+		return float32(cx + radius*math.Sin(a)), float32(cy - radius*math.Cos(a))
+	}
+
+	z.MoveTo(point(r, a0))
+	for i := 1; i <= n; i++ {
+		z.LineTo(point(r, a0+(a1-a0)*float64(i)/float64(n)))
+	}
+	// inner arc goes backwards, so the full ring has a hole
+	for i := n; i >= 0; i-- {
+		z.LineTo(point(inner, a0+(a1-a0)*float64(i)/float64(n)))
+	}
+	z.ClosePath()
+}
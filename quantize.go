@@ -0,0 +1,109 @@
+package countdown
+
+import (
+	"image/color"
+	"slices"
+)
+
+// colorCount is a color and the number of pixels of it
+type colorCount struct {
+	color color.RGBA
+	count int
+}
+
+// medianCut reduces colors to at most max by splitting the color space into boxes
+// along the widest channel at the median pixel, replacing each box with its average color.
+// Unlike picking the most frequent colors, it keeps rare but distinct colors,
+// e.g. text over an animated background.
+func medianCut(colors []colorCount, max int) color.Palette {
+	// sort colors first, so the result doesn't depend on map order
+	slices.SortFunc(colors, func(a, b colorCount) int {
+		return int(packRGBA(a.color)) - int(packRGBA(b.color))
+	})
+
+	boxes := [][]colorCount{colors}
+	for len(boxes) < max {
+		// split the box with the widest range weighted by the number of pixels
+		best, bestScore, bestChannel := -1, 0, 0
+		for i, box := range boxes {
+			if len(box) < 2 {
+				continue
+			}
+			channel, width := widestChannel(box)
+			if score := width * pixels(box); score > bestScore {
+				best, bestScore, bestChannel = i, score, channel
+			}
+		}
+		if best < 0 {
+			break
+		}
+
+		box := boxes[best]
+		slices.SortStableFunc(box, func(a, b colorCount) int {
+			return int(channelValue(a.color, bestChannel)) - int(channelValue(b.color, bestChannel))
+		})
+
+		// split at the median pixel, keeping both halves non-empty
+		half, sum, at := pixels(box)/2, 0, 1
+		for i, c := range box[:len(box)-1] {
+			sum += c.count
+			if sum >= half {
+				at = i + 1
+				break
+			}
+		}
+
+		boxes[best] = box[:at]
+		boxes = append(boxes, box[at:])
+	}
+
+	palette := make(color.Palette, len(boxes))
+	for i, box := range boxes {
+		palette[i] = averageColor(box)
+	}
+	return palette
+}
+
+func packRGBA(c color.RGBA) uint32 {
+	return uint32(c.R)<<24 | uint32(c.G)<<16 | uint32(c.B)<<8 | uint32(c.A)
+}
+
+func channelValue(c color.RGBA, channel int) uint8 {
+	return [4]uint8{c.R, c.G, c.B, c.A}[channel]
+}
+
+// widestChannel returns the channel with the widest range of values in the box
+func widestChannel(box []colorCount) (channel, width int) {
+	for ch := 0; ch < 4; ch++ {
+		lo, hi := uint8(255), uint8(0)
+		for _, c := range box {
+			v := channelValue(c.color, ch)
+			lo, hi = min(lo, v), max(hi, v)
+		}
+		if w := int(hi) - int(lo); w > width {
+			channel, width = ch, w
+		}
+	}
+	return channel, width
+}
+
+func pixels(box []colorCount) int {
+	n := 0
+	for _, c := range box {
+		n += c.count
+	}
+	return n
+}
+
+// averageColor returns the average color of the box weighted by the number of pixels
+func averageColor(box []colorCount) color.RGBA {
+	var r, g, b, a, n int
+	for _, c := range box {
+		r += int(c.color.R) * c.count
+		g += int(c.color.G) * c.count
+		b += int(c.color.B) * c.count
+		a += int(c.color.A) * c.count
+		n += c.count
+	}
+	return color.RGBA{uint8((r + n/2) / n), uint8((g + n/2) / n), uint8((b + n/2) / n), uint8((a + n/2) / n)}
+}
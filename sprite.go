@@ -0,0 +1,163 @@
+package countdown
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// spriteSheetRunes are glyphs of a sprite sheet from left to right
+const spriteSheetRunes = "0123456789:"
+
+// spriteFiles are file names of sprites in a directory
+var spriteFiles = map[rune]string{
+	'0': "0.png", '1': "1.png", '2': "2.png", '3': "3.png", '4': "4.png",
+	'5': "5.png", '6': "6.png", '7': "7.png", '8': "8.png", '9': "9.png",
+	':': "colon.png",
+}
+
+// SpriteFace is a font face drawing runes with images, e.g. hand-drawn digits.
+// Images are drawn in their own colors standing on the baseline,
+// the cap height is the height of the tallest image.
+// Runes without images are drawn with Fallback.
+type SpriteFace struct {
+	Images   map[rune]image.Image
+	Fallback font.Face
+}
+
+func (f *SpriteFace) Close() error {
+	return nil
+}
+
+func (f *SpriteFace) Glyph(dot fixed.Point26_6, r rune) (dr image.Rectangle, mask image.Image, maskp image.Point, advance fixed.Int26_6, ok bool) {
+	img, ok := f.Images[r]
+	if !ok {
+		return f.Fallback.Glyph(dot, r)
+	}
+
+	b := img.Bounds()
+	at := image.Pt(dot.X.Round(), dot.Y.Round()-b.Dy())
+	return image.Rectangle{at, at.Add(b.Size())}, img, b.Min, fixed.I(b.Dx()), true
+}
+
+func (f *SpriteFace) GlyphBounds(r rune) (bounds fixed.Rectangle26_6, advance fixed.Int26_6, ok bool) {
+	img, ok := f.Images[r]
+	if !ok {
+		return f.Fallback.GlyphBounds(r)
+	}
+
+	b := img.Bounds()
+	return fixed.R(0, -b.Dy(), b.Dx(), 0), fixed.I(b.Dx()), true
+}
+
+func (f *SpriteFace) GlyphAdvance(r rune) (advance fixed.Int26_6, ok bool) {
+	img, ok := f.Images[r]
+	if !ok {
+		return f.Fallback.GlyphAdvance(r)
+	}
+	return fixed.I(img.Bounds().Dx()), true
+}
+
+func (f *SpriteFace) Kern(r0, r1 rune) fixed.Int26_6 {
+	_, ok0 := f.Images[r0]
+	_, ok1 := f.Images[r1]
+	if ok0 || ok1 {
+		return 0
+	}
+	return f.Fallback.Kern(r0, r1)
+}
+
+// Metrics returns metrics of the fallback face with the height of sprites,
+// x-height is the cap height, as sprite colons are expected to be centered already
+func (f *SpriteFace) Metrics() font.Metrics {
+	h := 0
+	for _, img := range f.Images {
+		h = max(h, img.Bounds().Dy())
+	}
+
+	m := f.Fallback.Metrics()
+	m.Ascent = max(m.Ascent, fixed.I(h))
+	m.Height = max(m.Height, m.Ascent+m.Descent)
+	m.CapHeight = fixed.I(h)
+	m.XHeight = fixed.I(h)
+	return m
+}
+
+// sprite returns the image drawn for r, if the face is made of sprites
+func sprite(face font.Face, r rune) (image.Image, bool) {
+	f, ok := face.(*SpriteFace)
+	if !ok {
+		return nil, false
+	}
+	img, ok := f.Images[r]
+	return img, ok
+}
+
+// loadSpriteDir loads sprites from 0.png to 9.png and colon.png in the directory,
+// missing files are skipped
+func loadSpriteDir(dir string) (map[rune]image.Image, error) {
+	images := map[rune]image.Image{}
+	for r, name := range spriteFiles {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			continue
+		}
+
+		img, err := loadImage(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %v", name, err)
+		}
+		images[r] = *img
+	}
+
+	if len(images) == 0 {
+		return nil, fmt.Errorf("no sprites found in %s", dir)
+	}
+	return images, nil
+}
+
+// splitSpriteSheet cuts the sheet into glyphs of spriteSheetRunes of the same width,
+// trimming transparent columns on the sides, so glyphs keep their own widths.
+// Rows are kept, so all glyphs stand on the same baseline.
+func splitSpriteSheet(sheet image.Image) (map[rune]image.Image, error) {
+	b := sheet.Bounds()
+	n := len(spriteSheetRunes)
+	if b.Dx()%n != 0 {
+		return nil, fmt.Errorf("sprite sheet width %d is not divisible by %d glyphs %q", b.Dx(), n, spriteSheetRunes)
+	}
+
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), sheet, b.Min, draw.Src)
+
+	transparent := func(x int) bool {
+		for y := 0; y < b.Dy(); y++ {
+			if rgba.Pix[y*rgba.Stride+x*4+3] != 0 {
+				return false
+			}
+		}
+		return true
+	}
+
+	w := b.Dx() / n
+	images := map[rune]image.Image{}
+	for i, r := range spriteSheetRunes {
+		x0, x1 := i*w, (i+1)*w
+		for x0 < x1 && transparent(x0) {
+			x0++
+		}
+		for x1 > x0 && transparent(x1-1) {
+			x1--
+		}
+		if x0 == x1 {
+			// empty cell, the glyph is drawn with the fallback face
+			continue
+		}
+		images[r] = rgba.SubImage(image.Rect(x0, 0, x1, b.Dy()))
+	}
+	return images, nil
+}
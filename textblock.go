@@ -0,0 +1,146 @@
+package countdown
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// TextBlock is static text drawn above or below the timer, e.g. "Black Friday ends in".
+// It is wrapped into lines at spaces and newlines.
+type TextBlock struct {
+	Text     string
+	FontFace font.Face
+	FontSize float64
+	Color    color.Color // text color if nil
+	Width    int         // wrapping width in pixels, zero for the width of the frame without padding
+	Spacing  int         // gap between the block and the timer
+}
+
+// textLine is a line of a text block with the baseline starting at dot
+type textLine struct {
+	text  string
+	block *TextBlock
+	dot   fixed.Point26_6
+}
+
+// wrapText breaks text into lines not wider than width,
+// words longer than width are kept on their own lines
+func wrapText(face font.Face, text string, width fixed.Int26_6) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			if line == "" {
+				line = word
+				continue
+			}
+			if font.MeasureString(face, line+" "+word) > width {
+				lines = append(lines, line)
+				line = word
+				continue
+			}
+			line += " " + word
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// blockLines returns wrapped lines of the text block and their height,
+// or nothing if the block is empty
+func (g *Generator) blockLines(b *TextBlock, bounds image.Rectangle) ([]string, int) {
+	if strings.TrimSpace(b.Text) == "" {
+		return nil, 0
+	}
+
+	width := b.Width
+	if width == 0 {
+		width = bounds.Inset(g.TimerPadding).Dx()
+	}
+	lines := wrapText(b.FontFace, b.Text, fixed.I(width))
+	return lines, len(lines) * b.FontFace.Metrics().Height.Ceil()
+}
+
+// textBlocksHeight returns the height taken by the title and subtitle with spacing
+func (g *Generator) textBlocksHeight(bounds image.Rectangle) int {
+	height := 0
+	for _, b := range []*TextBlock{&g.Title, &g.Subtitle} {
+		if _, h := g.blockLines(b, bounds); h > 0 {
+			height += h + b.Spacing
+		}
+	}
+	return height
+}
+
+// placeTimer places the timer of size w×h with the title above and the subtitle below
+// as a single block, see placeBlock. Lines are aligned the same way as the block,
+// e.g. to the left with left anchors, so they don't move when the timer width changes.
+// It returns the top left corner of the timer and the lines of text blocks.
+func (g *Generator) placeTimer(bounds image.Rectangle, w, h fixed.Int26_6) (fixed.Point26_6, []textLine) {
+	title, titleHeight := g.blockLines(&g.Title, bounds)
+	subtitle, subtitleHeight := g.blockLines(&g.Subtitle, bounds)
+	if title == nil && subtitle == nil {
+		return g.placeBlock(bounds, w, h), nil
+	}
+
+	blockWidth, blockHeight := w, h
+	for _, b := range []struct {
+		block  *TextBlock
+		lines  []string
+		height int
+	}{{&g.Title, title, titleHeight}, {&g.Subtitle, subtitle, subtitleHeight}} {
+		for _, line := range b.lines {
+			blockWidth = max(blockWidth, font.MeasureString(b.block.FontFace, line))
+		}
+		if b.lines != nil {
+			blockHeight += fixed.I(b.height + b.block.Spacing)
+		}
+	}
+
+	top := g.placeBlock(bounds, blockWidth, blockHeight)
+	fx, _ := g.TimerAnchor.factors()
+	align := func(width fixed.Int26_6) fixed.Int26_6 {
+		return top.X + (blockWidth-width)*fixed.Int26_6(fx)/2
+	}
+
+	var lines []textLine
+	y := top.Y
+	addLines := func(b *TextBlock, text []string) {
+		m := b.FontFace.Metrics()
+		for _, line := range text {
+			width := font.MeasureString(b.FontFace, line)
+			lines = append(lines, textLine{text: line, block: b, dot: fixed.Point26_6{X: align(width), Y: y + m.Ascent}})
+			y += fixed.I(m.Height.Ceil())
+		}
+	}
+
+	if title != nil {
+		addLines(&g.Title, title)
+		y += fixed.I(g.Title.Spacing)
+	}
+	timer := fixed.Point26_6{X: align(w), Y: y}
+	y += h
+	if subtitle != nil {
+		y += fixed.I(g.Subtitle.Spacing)
+		addLines(&g.Subtitle, subtitle)
+	}
+
+	return timer, lines
+}
+
+// drawTextLines draws lines of text blocks in their colors
+func (g *Generator) drawTextLines(dst draw.Image, lines []textLine) {
+	for _, line := range lines {
+		c := line.block.Color
+		if c == nil {
+			c = g.TextColor
+		}
+		d := &font.Drawer{Dst: dst, Src: image.NewUniform(c), Face: line.block.FontFace, Dot: line.dot}
+		drawString(d, line.text)
+	}
+}